@@ -1,54 +1,153 @@
 package lru
 
-import "container/list"
+import "github.com/derekcdz/dsgym/list"
+
+// Key identifies an entry in the cache, it can be any comparable value, e.g. an int or a string
+type Key interface{}
+
+// Weigher returns the cost of an entry, it must return the same cost for the same entry
+// the cache evicts the least recently used entries until the total cost fits in its capacity
+type Weigher func(key Key, value interface{}) uint
 
 type LRUCache struct {
-	cap  uint
-	dict map[int]*list.Element
-	list *list.List
+	cap     uint
+	used    uint
+	weigh   Weigher
+	onEvict func(key Key, value interface{})
+	dict    map[Key]list.Handle
+	list    *list.PooledList
+}
+
+// Entry is a key-value pair given to PutMany
+type Entry struct {
+	Key   Key
+	Value interface{}
 }
 
 type entry struct {
-	key   int
+	key   Key
 	value interface{}
 }
 
+// New returns a cache which holds at most cap entries
 func New(cap uint) LRUCache {
+	return NewWeighted(cap, nil)
+}
+
+// NewWeighted returns a cache whose entries cost at most cap in total, the cost of each entry is given by w
+// a nil w counts every entry as 1, which is the same as New
+func NewWeighted(cap uint, w Weigher) LRUCache {
 	return LRUCache{
-		cap:  cap,
-		dict: make(map[int]*list.Element, 0),
-		list: list.New(),
+		cap:   cap,
+		weigh: w,
+		dict:  make(map[Key]list.Handle, 0),
+		list:  list.NewPooled(nil), // evicted elements are reused by later puts
 	}
 }
 
+// OnEvict registers f to be called with every entry evicted for lack of capacity
+// entries deleted by Remove or Clear are not reported
+func (c *LRUCache) OnEvict(f func(key Key, value interface{})) {
+	c.onEvict = f
+}
+
 func (c *LRUCache) Clear() {
-	*c = New(c.cap)
+	onEvict := c.onEvict
+	*c = NewWeighted(c.cap, c.weigh)
+	c.onEvict = onEvict
 }
 
-func (c *LRUCache) Get(key int) (result interface{}, hit bool) {
+// Len returns the number of entries in the cache
+func (c *LRUCache) Len() int {
+	return c.list.Len()
+}
+
+// Weight returns the total cost of the entries in the cache
+func (c *LRUCache) Weight() uint {
+	return c.used
+}
+
+func (c *LRUCache) weight(key Key, value interface{}) uint {
+	if c.weigh == nil {
+		return 1
+	}
+	return c.weigh(key, value)
+}
+
+func (c *LRUCache) Get(key Key) (result interface{}, hit bool) {
 	elem, found := c.dict[key]
 	hit = found
 	result = nil
 	if found {
-		result = (elem.Value).(entry).value
+		result = elem.Value().(entry).value
 		c.list.MoveToFront(elem)
 	}
 
 	return result, hit
 }
 
-func (c *LRUCache) Put(key int, value interface{}) {
+func (c *LRUCache) Put(key Key, value interface{}) {
+	c.put(key, value)
+}
+
+// put stores the entry and returns whether key was already in the cache
+func (c *LRUCache) put(key Key, value interface{}) (found bool) {
 	elem, found := c.dict[key]
 	if found {
-		elem.Value = entry{key, value}
+		old := elem.Value().(entry)
+		c.used -= c.weight(old.key, old.value)
+		elem.SetValue(entry{key, value})
 		c.list.MoveToFront(elem)
 	} else {
 		elem = c.list.PushFront(entry{key, value})
 		c.dict[key] = elem
-		if c.list.Len() > int(c.cap) {
-			backElem := c.list.Back()
-			delete(c.dict, backElem.Value.(entry).key)
-			c.list.Remove(backElem)
+	}
+	c.used += c.weight(key, value)
+	for c.used > c.cap && c.list.Len() > 0 {
+		backElem := c.list.Back()
+		e := c.remove(backElem)
+		if c.onEvict != nil {
+			c.onEvict(e.key, e.value)
 		}
 	}
+	return found
+}
+
+// GetMany looks up keys in order, as if Get were called for each of them
+// values[i] and hits[i] are the result of keys[i], and a key appearing twice is moved to the front twice
+func (c *LRUCache) GetMany(keys []Key) (values []interface{}, hits []bool) {
+	values = make([]interface{}, len(keys))
+	hits = make([]bool, len(keys))
+	for i, key := range keys {
+		values[i], hits[i] = c.Get(key)
+	}
+	return values, hits
+}
+
+// PutMany stores entries in order, as if Put were called for each of them
+// so the evicted entries are the same as with single puts, including entries of the batch itself when it does not fit
+// updated[i] is true when the key of entries[i] was in the cache and got its value replaced, false when it was inserted
+func (c *LRUCache) PutMany(entries []Entry) (updated []bool) {
+	updated = make([]bool, len(entries))
+	for i, e := range entries {
+		updated[i] = c.put(e.Key, e.Value)
+	}
+	return updated
+}
+
+// Remove deletes the entry of key from the cache, it returns whether the entry existed
+func (c *LRUCache) Remove(key Key) bool {
+	elem, found := c.dict[key]
+	if found {
+		c.remove(elem)
+	}
+	return found
+}
+
+func (c *LRUCache) remove(elem list.Handle) entry {
+	e := elem.Value().(entry)
+	delete(c.dict, e.key)
+	c.list.Remove(elem)
+	c.used -= c.weight(e.key, e.value)
+	return e
 }
@@ -18,7 +18,7 @@ func TestLRUCache_Put(t *testing.T) {
 
 	for i := 0; i < 5; i++ {
 		x := li.Front()
-		assert.Equal(t, entry{key: 10 - i, value: 10 - i}, x.Value)
+		assert.Equal(t, entry{key: 10 - i, value: 10 - i}, x.Value())
 		li.Remove(x)
 	}
 }
@@ -33,25 +33,136 @@ func TestLRUCache_Get(t *testing.T) {
 	x, hit := cache.Get(3)
 	assert.True(t, hit)
 	assert.Equal(t, 3, x)
-	assert.Equal(t, 3, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 3, cache.list.Front().Value().(entry).value)
 	x, hit = cache.Get(8)
 	assert.True(t, hit)
 	assert.Equal(t, 8, x)
-	assert.Equal(t, 8, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 8, cache.list.Front().Value().(entry).value)
 	x, hit = cache.Get(9)
 	assert.True(t, hit)
 	assert.Equal(t, 9, x)
-	assert.Equal(t, 9, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 9, cache.list.Front().Value().(entry).value)
 	x, hit = cache.Get(1)
 	assert.True(t, hit)
 	assert.Equal(t, 1, x)
-	assert.Equal(t, 1, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 1, cache.list.Front().Value().(entry).value)
 	x, hit = cache.Get(2)
 	assert.True(t, hit)
 	assert.Equal(t, 2, x)
-	assert.Equal(t, 2, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 2, cache.list.Front().Value().(entry).value)
 	x, hit = cache.Get(4)
 	assert.True(t, hit)
 	assert.Equal(t, 4, x)
-	assert.Equal(t, 4, cache.list.Front().Value.(entry).value)
+	assert.Equal(t, 4, cache.list.Front().Value().(entry).value)
+}
+
+func TestLRUCache_Remove(t *testing.T) {
+	cache := New(3)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	assert.True(t, cache.Remove("a"))
+	assert.False(t, cache.Remove("a"))
+	assert.Equal(t, 1, cache.Len())
+	_, hit := cache.Get("a")
+	assert.False(t, hit)
+	x, hit := cache.Get("b")
+	assert.True(t, hit)
+	assert.Equal(t, 2, x)
+}
+
+func TestLRUCache_Weighted(t *testing.T) {
+	cache := NewWeighted(10, func(key Key, value interface{}) uint {
+		return uint(len(value.(string)))
+	})
+	var evicted []Key
+	cache.OnEvict(func(key Key, value interface{}) {
+		evicted = append(evicted, key)
+	})
+
+	cache.Put(1, "aaaa")
+	cache.Put(2, "bbbb")
+	assert.Equal(t, uint(8), cache.Weight())
+	cache.Get(1)
+	cache.Put(3, "cc")
+	assert.Equal(t, uint(10), cache.Weight())
+	assert.Empty(t, evicted)
+
+	cache.Put(4, "d")
+	assert.Equal(t, []Key{2}, evicted)
+	assert.Equal(t, uint(7), cache.Weight())
+
+	cache.Put(3, "cccccc")
+	assert.Equal(t, []Key{2, 1}, evicted)
+	assert.Equal(t, uint(7), cache.Weight())
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Put(5, "eeeeeeeeeeee")
+	assert.Equal(t, []Key{2, 1, 4, 3, 5}, evicted)
+	assert.Zero(t, cache.Weight())
+	assert.Zero(t, cache.Len())
+}
+
+func TestLRUCache_GetMany(t *testing.T) {
+	cache := New(5)
+	for i := 1; i <= 5; i++ {
+		cache.Put(i, i*10)
+	}
+
+	values, hits := cache.GetMany([]Key{2, 7, 4, 2})
+	assert.Equal(t, []interface{}{20, nil, 40, 20}, values)
+	assert.Equal(t, []bool{true, false, true, true}, hits)
+
+	// recency follows the order of the keys: 2, 4, 2
+	assert.Equal(t, 2, cache.list.Front().Value().(entry).key)
+	assert.Equal(t, 4, cache.list.Front().Next().Value().(entry).key)
+	assert.Equal(t, 1, cache.list.Back().Value().(entry).key)
+
+	values, hits = cache.GetMany(nil)
+	assert.Empty(t, values)
+	assert.Empty(t, hits)
+}
+
+func TestLRUCache_PutMany(t *testing.T) {
+	single := New(3)
+	batch := New(3)
+	var evictedSingle, evictedBatch []Key
+	single.OnEvict(func(key Key, value interface{}) {
+		evictedSingle = append(evictedSingle, key)
+	})
+	batch.OnEvict(func(key Key, value interface{}) {
+		evictedBatch = append(evictedBatch, key)
+	})
+
+	entries := []Entry{{1, "a"}, {2, "b"}, {1, "c"}, {3, "d"}, {4, "e"}, {5, "f"}, {6, "g"}}
+	for _, e := range entries {
+		single.Put(e.Key, e.Value)
+	}
+	updated := batch.PutMany(entries)
+
+	assert.Equal(t, []bool{false, false, true, false, false, false, false}, updated)
+	assert.Equal(t, []Key{2, 1, 3}, evictedBatch)
+	assert.Equal(t, evictedSingle, evictedBatch)
+	values, hits := batch.GetMany([]Key{4, 5, 6})
+	assert.Equal(t, []interface{}{"e", "f", "g"}, values)
+	assert.Equal(t, []bool{true, true, true}, hits)
+
+	// a key evicted earlier in the batch is inserted again
+	updated = batch.PutMany([]Entry{{6, "h"}, {7, "i"}, {4, "j"}, {7, "k"}})
+	assert.Equal(t, []bool{true, false, false, true}, updated)
+	assert.Equal(t, []Key{2, 1, 3, 4, 5}, evictedBatch)
+	assert.Empty(t, batch.PutMany(nil))
+}
+
+// BenchmarkLRUCache_PutEvict keeps the cache full, so every put evicts the back entry and reuses its element
+func BenchmarkLRUCache_PutEvict(b *testing.B) {
+	cache := New(1024)
+	for i := 0; i < 1024; i++ {
+		cache.Put(i, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Put(1024+i, i)
+	}
 }
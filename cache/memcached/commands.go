@@ -0,0 +1,502 @@
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errLineTooLong = errors.New("memcached: line too long")
+
+// session holds the buffered reader and writer of one client connection
+type session struct {
+	s *Server
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func (c *session) reply(line string) {
+	c.w.WriteString(line)
+	c.w.WriteString("\r\n")
+}
+
+func (c *session) readLine() (string, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull && isRetrieval(line) {
+		buf := append([]byte(nil), line...)
+		for err == bufio.ErrBufferFull && len(buf) <= maxGetLineLength {
+			line, err = c.r.ReadSlice('\n')
+			buf = append(buf, line...)
+		}
+		if len(buf) > maxGetLineLength {
+			return "", errLineTooLong
+		}
+		line = buf
+	}
+	if err == bufio.ErrBufferFull {
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(line, "\r\n")), nil
+}
+
+// isRetrieval returns whether line starts a get or gets command, whose line may be longer than maxLineLength
+func isRetrieval(line []byte) bool {
+	return bytes.HasPrefix(line, []byte("get ")) || bytes.HasPrefix(line, []byte("gets "))
+}
+
+// handle reads and executes one command, quit is true when the connection should be closed
+func (c *session) handle() (quit bool, err error) {
+	line, err := c.readLine()
+	if err == errLineTooLong {
+		c.reply("CLIENT_ERROR line too long")
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		c.reply("ERROR")
+		return false, nil
+	}
+	cmd, args := fields[0], fields[1:]
+	switch cmd {
+	case "get", "gets":
+		c.get(args, cmd == "gets")
+	case "set", "add", "replace", "cas":
+		err = c.store(cmd, args)
+	case "delete":
+		c.delete(args)
+	case "incr", "decr":
+		c.incr(args, cmd == "decr")
+	case "stats":
+		c.stats(args)
+	case "flush_all":
+		c.flushAll(args)
+	case "version":
+		c.reply("VERSION " + Version)
+	case "quit":
+		return true, nil
+	default:
+		c.reply("ERROR")
+	}
+	return false, err
+}
+
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// noreply strips a trailing "noreply" from args and reports whether it was there
+func noreply(args []string) ([]string, bool) {
+	if len(args) > 0 && args[len(args)-1] == "noreply" {
+		return args[:len(args)-1], true
+	}
+	return args, false
+}
+
+// get <key>*
+// gets <key>*
+func (c *session) get(keys []string, withCas bool) {
+	if len(keys) == 0 {
+		c.reply("ERROR")
+		return
+	}
+	for _, key := range keys {
+		if !validKey(key) {
+			c.reply("CLIENT_ERROR bad command line format")
+			return
+		}
+	}
+
+	items := c.s.getMulti(keys)
+	for i, it := range items {
+		if it == nil {
+			continue
+		}
+		if withCas {
+			fmt.Fprintf(c.w, "VALUE %s %d %d %d\r\n", keys[i], it.flags, len(it.data), it.cas)
+		} else {
+			fmt.Fprintf(c.w, "VALUE %s %d %d\r\n", keys[i], it.flags, len(it.data))
+		}
+		c.w.Write(it.data)
+		c.reply("")
+	}
+	c.reply("END")
+}
+
+type storeRequest struct {
+	key     string
+	flags   uint32
+	exptime int64
+	size    int
+	cas     uint64
+	noreply bool
+}
+
+func parseStore(cmd string, args []string) (req storeRequest, ok bool) {
+	args, req.noreply = noreply(args)
+	want := 4
+	if cmd == "cas" {
+		want = 5
+	}
+	if len(args) != want || !validKey(args[0]) {
+		return req, false
+	}
+	req.key = args[0]
+
+	flags, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return req, false
+	}
+	req.flags = uint32(flags)
+	if req.exptime, err = strconv.ParseInt(args[2], 10, 64); err != nil {
+		return req, false
+	}
+	if req.size, err = strconv.Atoi(args[3]); err != nil || req.size < 0 {
+		return req, false
+	}
+	if cmd == "cas" {
+		if req.cas, err = strconv.ParseUint(args[4], 10, 64); err != nil {
+			return req, false
+		}
+	}
+	return req, true
+}
+
+// <set|add|replace> <key> <flags> <exptime> <bytes> [noreply]
+// cas <key> <flags> <exptime> <bytes> <cas unique> [noreply]
+func (c *session) store(cmd string, args []string) error {
+	req, ok := parseStore(cmd, args)
+	if !ok {
+		c.reply("CLIENT_ERROR bad command line format")
+		return nil
+	}
+	if req.size > c.s.itemSizeLimit() {
+		if _, err := c.r.Discard(req.size + 2); err != nil {
+			return err
+		}
+		c.reply("SERVER_ERROR object too large for cache")
+		return nil
+	}
+
+	data := make([]byte, req.size+2)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		c.reply("CLIENT_ERROR bad data chunk")
+		return nil
+	}
+
+	res := c.s.store(cmd, req, data[:req.size])
+	if !req.noreply {
+		c.reply(res)
+	}
+	return nil
+}
+
+// delete <key> [noreply]
+func (c *session) delete(args []string) {
+	args, quiet := noreply(args)
+	// a zero hold time is still accepted for compatibility with old clients
+	if len(args) == 2 && args[1] == "0" {
+		args = args[:1]
+	}
+	if len(args) != 1 || !validKey(args[0]) {
+		c.reply("CLIENT_ERROR bad command line format")
+		return
+	}
+	res := c.s.delete(args[0])
+	if !quiet {
+		c.reply(res)
+	}
+}
+
+// <incr|decr> <key> <value> [noreply]
+func (c *session) incr(args []string, decr bool) {
+	args, quiet := noreply(args)
+	if len(args) != 2 || !validKey(args[0]) {
+		c.reply("CLIENT_ERROR bad command line format")
+		return
+	}
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		c.reply("CLIENT_ERROR invalid numeric delta argument")
+		return
+	}
+	res := c.s.incr(args[0], delta, decr)
+	if !quiet {
+		c.reply(res)
+	}
+}
+
+// stats
+func (c *session) stats(args []string) {
+	if len(args) != 0 {
+		c.reply("ERROR")
+		return
+	}
+	for _, stat := range c.s.snapshot() {
+		c.reply("STAT " + stat[0] + " " + stat[1])
+	}
+	c.reply("END")
+}
+
+// flush_all [delay] [noreply]
+func (c *session) flushAll(args []string) {
+	args, quiet := noreply(args)
+	var delay int64
+	if len(args) > 1 {
+		c.reply("CLIENT_ERROR bad command line format")
+		return
+	}
+	if len(args) == 1 {
+		var err error
+		if delay, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			c.reply("CLIENT_ERROR bad command line format")
+			return
+		}
+	}
+	c.s.flushAll(delay)
+	if !quiet {
+		c.reply("OK")
+	}
+}
+
+func (it *item) expired(now time.Time) bool {
+	return !it.expires.IsZero() && !now.Before(it.expires)
+}
+
+// expiry converts an exptime of the protocol into an absolute time
+// 0 never expires, a negative value is already expired, values up to 30 days are relative to now
+func (s *Server) expiry(exptime int64) time.Time {
+	switch {
+	case exptime == 0:
+		return time.Time{}
+	case exptime < 0:
+		return s.now()
+	case exptime <= relativeExpiry:
+		return s.now().Add(time.Duration(exptime) * time.Second)
+	default:
+		return time.Unix(exptime, 0)
+	}
+}
+
+func (s *Server) itemSizeLimit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxItemSize
+}
+
+// lookup returns the live item of key and marks it as recently used, expired items are dropped
+// s.mu must be held
+func (s *Server) lookup(key string) *item {
+	s.flushDue(s.now())
+	v, hit := s.cache.Get(key)
+	if !hit {
+		return nil
+	}
+	it := v.(*item)
+	if it.expired(s.now()) {
+		s.cache.Remove(key)
+		return nil
+	}
+	return it
+}
+
+// put stores it under key with a fresh cas unique
+// s.mu must be held
+func (s *Server) put(key string, it *item) {
+	s.casSeq++
+	it.cas = s.casSeq
+	s.cache.Put(key, it)
+}
+
+func (s *Server) getMulti(keys []string) []*item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]*item, len(keys))
+	for i, key := range keys {
+		s.stats.cmdGet++
+		items[i] = s.lookup(key)
+		if items[i] == nil {
+			s.stats.getMisses++
+		} else {
+			s.stats.getHits++
+		}
+	}
+	return items
+}
+
+func (s *Server) store(cmd string, req storeRequest, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.cmdSet++
+
+	old := s.lookup(req.key)
+	switch cmd {
+	case "add":
+		if old != nil {
+			return "NOT_STORED"
+		}
+	case "replace":
+		if old == nil {
+			return "NOT_STORED"
+		}
+	case "cas":
+		if old == nil {
+			s.stats.casMisses++
+			return "NOT_FOUND"
+		}
+		if old.cas != req.cas {
+			s.stats.casBadval++
+			return "EXISTS"
+		}
+	}
+
+	it := &item{
+		flags:   req.flags,
+		expires: s.expiry(req.exptime),
+		data:    data,
+	}
+	if weigh(req.key, it) > s.maxBytes {
+		return "SERVER_ERROR out of memory storing object"
+	}
+	if cmd == "cas" {
+		s.stats.casHits++
+	}
+	if it.expired(s.now()) {
+		// storing an already expired item behaves like a delete
+		s.cache.Remove(req.key)
+		return "STORED"
+	}
+	s.put(req.key, it)
+	s.stats.totalItems++
+	return "STORED"
+}
+
+func (s *Server) delete(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lookup(key) == nil {
+		s.stats.deleteMiss++
+		return "NOT_FOUND"
+	}
+	s.cache.Remove(key)
+	s.stats.deleteHits++
+	return "DELETED"
+}
+
+func (s *Server) incr(key string, delta uint64, decr bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.lookup(key)
+	hits, misses := &s.stats.incrHits, &s.stats.incrMisses
+	if decr {
+		hits, misses = &s.stats.decrHits, &s.stats.decrMisses
+	}
+	if old == nil {
+		*misses++
+		return "NOT_FOUND"
+	}
+	n, err := strconv.ParseUint(string(old.data), 10, 64)
+	if err != nil {
+		return "CLIENT_ERROR cannot increment or decrement non-numeric value"
+	}
+	*hits++
+
+	if !decr {
+		n += delta // wraps around at 64 bits like memcached
+	} else if delta > n {
+		n = 0
+	} else {
+		n -= delta
+	}
+	// items are never modified in place, readers may still hold old.data
+	s.put(key, &item{
+		flags:   old.flags,
+		expires: old.expires,
+		data:    strconv.AppendUint(nil, n, 10),
+	})
+	return strconv.FormatUint(n, 10)
+}
+
+// flushAll drops all items, at once or when delay has passed, delay is read like an exptime
+// like memcached, a delayed flush also drops the items stored until it happens
+func (s *Server) flushAll(delay int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.cmdFlush++
+	s.flushAt = s.expiry(delay)
+	if s.flushAt.IsZero() {
+		s.cache.Clear()
+		return
+	}
+	s.flushDue(s.now())
+}
+
+// flushDue carries out a pending flush whose time has come
+// s.mu must be held
+func (s *Server) flushDue(now time.Time) {
+	if !s.flushAt.IsZero() && !now.Before(s.flushAt) {
+		s.cache.Clear()
+		s.flushAt = time.Time{}
+	}
+}
+
+func (s *Server) snapshot() [][2]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.flushDue(now)
+	u := func(n uint64) string {
+		return strconv.FormatUint(n, 10)
+	}
+	return [][2]string{
+		{"pid", strconv.Itoa(os.Getpid())},
+		{"uptime", strconv.FormatInt(int64(now.Sub(s.started)/time.Second), 10)},
+		{"time", strconv.FormatInt(now.Unix(), 10)},
+		{"version", Version},
+		{"curr_connections", strconv.Itoa(s.stats.currentConn)},
+		{"total_connections", u(s.stats.totalConns)},
+		{"cmd_get", u(s.stats.cmdGet)},
+		{"cmd_set", u(s.stats.cmdSet)},
+		{"cmd_flush", u(s.stats.cmdFlush)},
+		{"get_hits", u(s.stats.getHits)},
+		{"get_misses", u(s.stats.getMisses)},
+		{"delete_hits", u(s.stats.deleteHits)},
+		{"delete_misses", u(s.stats.deleteMiss)},
+		{"incr_hits", u(s.stats.incrHits)},
+		{"incr_misses", u(s.stats.incrMisses)},
+		{"decr_hits", u(s.stats.decrHits)},
+		{"decr_misses", u(s.stats.decrMisses)},
+		{"cas_hits", u(s.stats.casHits)},
+		{"cas_misses", u(s.stats.casMisses)},
+		{"cas_badval", u(s.stats.casBadval)},
+		{"curr_items", strconv.Itoa(s.cache.Len())},
+		{"total_items", u(s.stats.totalItems)},
+		{"bytes", strconv.FormatUint(uint64(s.cache.Weight()), 10)},
+		{"limit_maxbytes", strconv.FormatUint(uint64(s.maxBytes), 10)},
+		{"evictions", u(s.stats.evictions)},
+	}
+}
@@ -0,0 +1,202 @@
+// A memcached server speaking the ASCII protocol, the items are stored in an LRUCache
+// which evicts the least recently used items when the stored bytes exceed the budget
+package memcached
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	lru "github.com/derekcdz/dsgym/cache"
+)
+
+const (
+	Version = "1.6.0-dsgym"
+
+	// MaxKeyLength is the longest key accepted by the protocol
+	MaxKeyLength = 250
+	// DefaultMaxItemSize is the largest data block accepted unless changed by SetMaxItemSize
+	DefaultMaxItemSize = 1 << 20
+
+	// itemOverhead is charged for every item on top of its key and data
+	itemOverhead = 48
+	// maxLineLength bounds a command line so a client can not make the server buffer forever
+	maxLineLength = 2048
+	// maxGetLineLength bounds the line of get and gets instead, which is as long as the key list of a multi-get,
+	// it holds thousands of keys of the maximum length
+	maxGetLineLength = 1 << 20
+	// relativeExpiry is the largest exptime treated as an offset from now, larger values are unix times
+	relativeExpiry = 60 * 60 * 24 * 30
+)
+
+var ErrServerClosed = errors.New("memcached: server closed")
+
+type item struct {
+	flags   uint32
+	expires time.Time // zero means never
+	cas     uint64
+	data    []byte
+}
+
+func weigh(key lru.Key, value interface{}) uint {
+	return uint(len(key.(string)) + len(value.(*item).data) + itemOverhead)
+}
+
+type stats struct {
+	totalConns  uint64
+	cmdGet      uint64
+	cmdSet      uint64
+	cmdFlush    uint64
+	getHits     uint64
+	getMisses   uint64
+	deleteHits  uint64
+	deleteMiss  uint64
+	incrHits    uint64
+	incrMisses  uint64
+	decrHits    uint64
+	decrMisses  uint64
+	casHits     uint64
+	casMisses   uint64
+	casBadval   uint64
+	totalItems  uint64
+	evictions   uint64
+	currentConn int
+}
+
+type Server struct {
+	mu          sync.Mutex
+	cache       lru.LRUCache
+	maxBytes    uint
+	maxItemSize int
+	casSeq      uint64
+	flushAt     time.Time // set by flush_all with a delay, zero when no flush is pending
+	stats       stats
+	started     time.Time
+	now         func() time.Time
+
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New returns a server which keeps at most maxBytes bytes of items, counting keys, data and a fixed per-item overhead
+func New(maxBytes uint) *Server {
+	s := &Server{
+		cache:       lru.NewWeighted(maxBytes, weigh),
+		maxBytes:    maxBytes,
+		maxItemSize: DefaultMaxItemSize,
+		now:         time.Now,
+		listeners:   make(map[net.Listener]struct{}),
+		conns:       make(map[net.Conn]struct{}),
+	}
+	s.started = s.now()
+	s.cache.OnEvict(func(lru.Key, interface{}) {
+		s.stats.evictions++
+	})
+	return s
+}
+
+// SetMaxItemSize changes the largest data block accepted by storage commands
+func (s *Server) SetMaxItemSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxItemSize = n
+}
+
+// ListenAndServe listens on the TCP address addr and serves connections until Close is called
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln and serves each of them in its own goroutine
+// it always returns a non-nil error, ErrServerClosed after Close is called
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, ln)
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.stats.totalConns++
+		s.stats.currentConn++
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops all listeners, closes active connections and waits for their goroutines to return
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for ln := range s.listeners {
+		ln.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.stats.currentConn--
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	c := &session{
+		s: s,
+		r: bufio.NewReaderSize(conn, maxLineLength),
+		w: bufio.NewWriter(conn),
+	}
+	for {
+		quit, err := c.handle()
+		if err != nil || quit {
+			c.w.Flush()
+			return
+		}
+		// flush once the pipelined commands are consumed, so that a batch needs one write
+		if c.r.Buffered() == 0 {
+			if err := c.w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
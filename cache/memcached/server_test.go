@@ -0,0 +1,483 @@
+package memcached
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// startServer serves s on a loopback port and returns its address
+func startServer(t *testing.T, s *Server) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ln)
+	}()
+	t.Cleanup(func() {
+		s.Close()
+		assert.Equal(t, ErrServerClosed, <-done)
+	})
+	return ln.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *client {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func newClient(t *testing.T, maxBytes uint) (*Server, *client) {
+	s := New(maxBytes)
+	return s, dial(t, startServer(t, s))
+}
+
+func (c *client) send(lines ...string) {
+	_, err := c.conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+	require.NoError(c.t, err)
+}
+
+func (c *client) line() string {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.r.ReadString('\n')
+	require.NoError(c.t, err)
+	require.True(c.t, strings.HasSuffix(line, "\r\n"), "line %q is not terminated by CRLF", line)
+	return strings.TrimSuffix(line, "\r\n")
+}
+
+// lines reads until a line equals last, including it
+func (c *client) lines(last string) []string {
+	var res []string
+	for {
+		l := c.line()
+		res = append(res, l)
+		if l == last {
+			return res
+		}
+	}
+}
+
+func (c *client) call(cmd string, expected string) {
+	c.send(cmd)
+	assert.Equal(c.t, expected, c.line(), cmd)
+}
+
+func (c *client) set(key, value string) {
+	c.send(fmt.Sprintf("set %s 0 0 %d", key, len(value)), value)
+	assert.Equal(c.t, "STORED", c.line())
+}
+
+func (c *client) stats() map[string]string {
+	c.send("stats")
+	res := make(map[string]string)
+	for _, l := range c.lines("END") {
+		if l == "END" {
+			break
+		}
+		parts := strings.SplitN(l, " ", 3)
+		require.Len(c.t, parts, 3)
+		assert.Equal(c.t, "STAT", parts[0])
+		res[parts[1]] = parts[2]
+	}
+	return res
+}
+
+func TestServer_SetGet(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.send("get foo")
+	assert.Equal(t, []string{"END"}, c.lines("END"))
+
+	c.send("set foo 42 0 5", "hello")
+	assert.Equal(t, "STORED", c.line())
+	c.send("get foo")
+	assert.Equal(t, []string{"VALUE foo 42 5", "hello", "END"}, c.lines("END"))
+
+	c.set("bar", "with spaces\r\ninside")
+	c.send("get foo missing bar")
+	assert.Equal(t, []string{
+		"VALUE foo 42 5", "hello",
+		"VALUE bar 0 19", "with spaces", "inside",
+		"END",
+	}, c.lines("END"))
+
+	c.set("empty", "")
+	c.send("get empty")
+	assert.Equal(t, []string{"VALUE empty 0 0", "", "END"}, c.lines("END"))
+}
+
+func TestServer_AddReplace(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.send("replace k 0 0 1", "a")
+	assert.Equal(t, "NOT_STORED", c.line())
+	c.send("add k 0 0 1", "b")
+	assert.Equal(t, "STORED", c.line())
+	c.send("add k 0 0 1", "c")
+	assert.Equal(t, "NOT_STORED", c.line())
+	c.send("replace k 7 0 1", "d")
+	assert.Equal(t, "STORED", c.line())
+	c.send("get k")
+	assert.Equal(t, []string{"VALUE k 7 1", "d", "END"}, c.lines("END"))
+}
+
+func TestServer_Cas(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.send("cas k 0 0 1 1", "a")
+	assert.Equal(t, "NOT_FOUND", c.line())
+
+	c.set("k", "a")
+	c.send("gets k")
+	res := c.lines("END")
+	require.Len(t, res, 3)
+	var unique uint64
+	_, err := fmt.Sscanf(res[0], "VALUE k 0 1 %d", &unique)
+	require.NoError(t, err)
+
+	c.send(fmt.Sprintf("cas k 0 0 1 %d", unique+100), "b")
+	assert.Equal(t, "EXISTS", c.line())
+	c.send(fmt.Sprintf("cas k 0 0 1 %d", unique), "c")
+	assert.Equal(t, "STORED", c.line())
+	// the unique changes with every write
+	c.send(fmt.Sprintf("cas k 0 0 1 %d", unique), "d")
+	assert.Equal(t, "EXISTS", c.line())
+
+	c.send("get k")
+	assert.Equal(t, []string{"VALUE k 0 1", "c", "END"}, c.lines("END"))
+
+	st := c.stats()
+	assert.Equal(t, "1", st["cas_hits"])
+	assert.Equal(t, "1", st["cas_misses"])
+	assert.Equal(t, "2", st["cas_badval"])
+}
+
+// a cas whose item does not fit is not a hit
+func TestServer_CasOutOfMemory(t *testing.T) {
+	_, c := newClient(t, 100)
+
+	c.set("k", "a")
+	c.send("gets k")
+	res := c.lines("END")
+	require.Len(t, res, 3)
+	var unique uint64
+	_, err := fmt.Sscanf(res[0], "VALUE k 0 1 %d", &unique)
+	require.NoError(t, err)
+
+	c.send(fmt.Sprintf("cas k 0 0 200 %d", unique), strings.Repeat("b", 200))
+	assert.Equal(t, "SERVER_ERROR out of memory storing object", c.line())
+	assert.Equal(t, "0", c.stats()["cas_hits"])
+}
+
+func TestServer_Delete(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.set("k", "v")
+	c.call("delete k", "DELETED")
+	c.call("delete k", "NOT_FOUND")
+	c.send("get k")
+	assert.Equal(t, []string{"END"}, c.lines("END"))
+
+	c.set("k", "v")
+	c.call("delete k 0", "DELETED")
+}
+
+func TestServer_IncrDecr(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.call("incr n 1", "NOT_FOUND")
+	c.set("n", "10")
+	c.call("incr n 5", "15")
+	c.call("decr n 3", "12")
+	c.call("decr n 100", "0")
+	c.call("incr n 18446744073709551615", "18446744073709551615")
+	c.call("incr n 2", "1")
+	c.call("incr n x", "CLIENT_ERROR invalid numeric delta argument")
+
+	c.set("s", "abc")
+	c.call("incr s 1", "CLIENT_ERROR cannot increment or decrement non-numeric value")
+
+	c.set("n", "99")
+	c.call("incr n 1", "100")
+	c.send("get n")
+	assert.Equal(t, []string{"VALUE n 0 3", "100", "END"}, c.lines("END"))
+}
+
+func TestServer_Noreply(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.send("set a 0 0 1 noreply", "1")
+	c.send("add a 0 0 1 noreply", "2")
+	c.send("incr a 4 noreply")
+	c.send("set b 0 0 1 noreply", "x")
+	c.send("delete b noreply")
+	c.send("get a b")
+	assert.Equal(t, []string{"VALUE a 0 1", "5", "END"}, c.lines("END"))
+}
+
+func TestServer_Pipelining(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.send("set a 0 0 1", "1", "set b 0 0 1", "2", "get a b", "delete a", "version")
+	assert.Equal(t, "STORED", c.line())
+	assert.Equal(t, "STORED", c.line())
+	assert.Equal(t, []string{"VALUE a 0 1", "1", "VALUE b 0 1", "2", "END"}, c.lines("END"))
+	assert.Equal(t, "DELETED", c.line())
+	assert.Equal(t, "VERSION "+Version, c.line())
+}
+
+func TestServer_Errors(t *testing.T) {
+	s, c := newClient(t, 1<<20)
+	s.SetMaxItemSize(8)
+
+	c.call("bogus", "ERROR")
+	c.call("", "ERROR")
+	c.call("set k 0 0", "CLIENT_ERROR bad command line format")
+	c.call("set k x 0 1", "CLIENT_ERROR bad command line format")
+	c.call("get "+strings.Repeat("k", MaxKeyLength+1), "CLIENT_ERROR bad command line format")
+
+	c.send("set k 0 0 1", "toolong")
+	assert.Equal(t, "CLIENT_ERROR bad data chunk", c.line())
+	// the remaining bytes of the bad chunk are read as a command
+	assert.Equal(t, "ERROR", c.line())
+
+	c.send("set big 0 0 9", "123456789")
+	assert.Equal(t, "SERVER_ERROR object too large for cache", c.line())
+	c.send("get big")
+	assert.Equal(t, []string{"END"}, c.lines("END"))
+
+	// the connection is still usable
+	c.set("k", "v")
+}
+
+func TestServer_LongLines(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	// a multi-get may be far longer than the line of any other command
+	keys := make([]string, 2000)
+	expected := []string{}
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%036d", i)
+		if i%100 == 0 {
+			c.set(keys[i], keys[i])
+			expected = append(expected, fmt.Sprintf("VALUE %s 0 %d", keys[i], len(keys[i])), keys[i])
+		}
+	}
+	expected = append(expected, "END")
+	line := "get " + strings.Join(keys, " ")
+	assert.Greater(t, len(line), maxLineLength*30)
+	c.send(line)
+	assert.Equal(t, expected, c.lines("END"))
+	c.send("gets " + strings.Join(keys, " "))
+	assert.Len(t, c.lines("END"), len(expected))
+
+	// other commands are still bounded
+	c.call("set "+strings.Repeat("k", maxLineLength)+" 0 0 1", "CLIENT_ERROR line too long")
+	_, err := c.r.ReadString('\n')
+	assert.Error(t, err, "the connection is closed")
+
+	c = dial(t, c.conn.RemoteAddr().String())
+	c.call("get "+strings.Repeat("k ", maxGetLineLength/2+1), "CLIENT_ERROR line too long")
+}
+
+func TestServer_Expiry(t *testing.T) {
+	s, c := newClient(t, 1<<20)
+	now := time.Unix(1000000000, 0)
+	var mu sync.Mutex
+	s.mu.Lock()
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	s.mu.Unlock()
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	c.send("set rel 0 10 1", "r")
+	assert.Equal(t, "STORED", c.line())
+	c.send(fmt.Sprintf("set abs 0 %d 1", now.Unix()+20), "a")
+	assert.Equal(t, "STORED", c.line())
+	c.send("set never 0 0 1", "n")
+	assert.Equal(t, "STORED", c.line())
+	c.send("set gone 0 -1 1", "g")
+	assert.Equal(t, "STORED", c.line())
+
+	c.send("get rel abs never gone")
+	assert.Equal(t, []string{"VALUE rel 0 1", "r", "VALUE abs 0 1", "a", "VALUE never 0 1", "n", "END"}, c.lines("END"))
+
+	advance(10 * time.Second)
+	c.send("get rel abs never")
+	assert.Equal(t, []string{"VALUE abs 0 1", "a", "VALUE never 0 1", "n", "END"}, c.lines("END"))
+	c.send("replace rel 0 0 1", "x")
+	assert.Equal(t, "NOT_STORED", c.line())
+
+	advance(10 * time.Second)
+	c.send("get rel abs never")
+	assert.Equal(t, []string{"VALUE never 0 1", "n", "END"}, c.lines("END"))
+	assert.Equal(t, "1", c.stats()["curr_items"])
+}
+
+func TestServer_Eviction(t *testing.T) {
+	// room for exactly three items with a one byte key and ten bytes of data
+	const size = 1 + 10 + itemOverhead
+	_, c := newClient(t, 3*size)
+
+	value := strings.Repeat("v", 10)
+	c.set("a", value)
+	c.set("b", value)
+	c.set("c", value)
+	c.send("get a")
+	c.lines("END")
+	c.set("d", value)
+
+	c.send("get a b c d")
+	assert.Equal(t, []string{
+		"VALUE a 0 10", value,
+		"VALUE c 0 10", value,
+		"VALUE d 0 10", value,
+		"END",
+	}, c.lines("END"))
+
+	// a larger item pushes out as many old ones as it needs
+	c.set("e", strings.Repeat("v", 10+size))
+	c.send("get a c d e")
+	assert.Equal(t, []string{
+		"VALUE d 0 10", value,
+		"VALUE e 0 69", strings.Repeat("v", 10+size),
+		"END",
+	}, c.lines("END"))
+
+	st := c.stats()
+	assert.Equal(t, "3", st["evictions"])
+	assert.Equal(t, fmt.Sprint(3*size), st["limit_maxbytes"])
+	assert.Equal(t, fmt.Sprint(3*size), st["bytes"])
+
+	c.send("set huge 0 0 200", strings.Repeat("h", 200))
+	assert.Equal(t, "SERVER_ERROR out of memory storing object", c.line())
+}
+
+func TestServer_Stats(t *testing.T) {
+	_, c := newClient(t, 1<<20)
+
+	c.set("a", "1")
+	c.set("b", "22")
+	c.send("get a x")
+	c.lines("END")
+	c.call("delete b", "DELETED")
+	c.call("delete b", "NOT_FOUND")
+
+	st := c.stats()
+	assert.Equal(t, "2", st["cmd_get"])
+	assert.Equal(t, "2", st["cmd_set"])
+	assert.Equal(t, "1", st["get_hits"])
+	assert.Equal(t, "1", st["get_misses"])
+	assert.Equal(t, "1", st["delete_hits"])
+	assert.Equal(t, "1", st["delete_misses"])
+	assert.Equal(t, "1", st["curr_items"])
+	assert.Equal(t, "2", st["total_items"])
+	assert.Equal(t, fmt.Sprint(1+1+itemOverhead), st["bytes"])
+	assert.Equal(t, "1", st["curr_connections"])
+	assert.Equal(t, Version, st["version"])
+
+	c.call("flush_all", "OK")
+	assert.Equal(t, "0", c.stats()["curr_items"])
+}
+
+func TestServer_FlushAllDelay(t *testing.T) {
+	s, c := newClient(t, 1<<20)
+	now := time.Unix(1000000000, 0)
+	var mu sync.Mutex
+	s.mu.Lock()
+	s.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	s.mu.Unlock()
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	c.set("a", "1")
+	c.call("flush_all 0", "OK")
+	c.send("get a")
+	assert.Equal(t, []string{"END"}, c.lines("END"))
+
+	c.set("a", "1")
+	c.call("flush_all 10", "OK")
+	c.set("b", "2")
+	advance(9 * time.Second)
+	c.send("get a b")
+	assert.Equal(t, []string{"VALUE a 0 1", "1", "VALUE b 0 1", "2", "END"}, c.lines("END"))
+
+	// the items stored before the deadline go too
+	advance(time.Second)
+	c.send("get a b")
+	assert.Equal(t, []string{"END"}, c.lines("END"))
+	c.set("c", "3")
+	c.send("get c")
+	assert.Equal(t, []string{"VALUE c 0 1", "3", "END"}, c.lines("END"))
+
+	c.send("flush_all 5 noreply")
+	c.send("get c")
+	assert.Equal(t, []string{"VALUE c 0 1", "3", "END"}, c.lines("END"))
+	advance(5 * time.Second)
+	assert.Equal(t, "0", c.stats()["curr_items"])
+	assert.Equal(t, "3", c.stats()["cmd_flush"])
+
+	c.call("flush_all soon", "CLIENT_ERROR bad command line format")
+	c.call("flush_all 1 2", "CLIENT_ERROR bad command line format")
+}
+
+func TestServer_ConcurrentClients(t *testing.T) {
+	s := New(1 << 20)
+	addr := startServer(t, s)
+	c := dial(t, addr)
+	c.set("counter", "0")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		conn, err := net.Dial("tcp", addr)
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			for j := 0; j < 50; j++ {
+				if _, err := conn.Write([]byte("incr counter 1\r\n")); err != nil {
+					return
+				}
+				if _, err := r.ReadString('\n'); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.send("get counter")
+	assert.Equal(t, []string{"VALUE counter 0 3", "400", "END"}, c.lines("END"))
+}
@@ -0,0 +1,176 @@
+// A distributed read-through cache in the style of groupcache
+// every key is owned by one peer picked by a consistent hash ring, the owner loads the value and caches it
+// the other peers fetch it from the owner and keep recently fetched values in a smaller hot cache
+package peer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	lru "github.com/derekcdz/dsgym/cache"
+)
+
+// Getter loads the value of a key on a cache miss of its owner
+type Getter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+type GetterFunc func(ctx context.Context, key string) ([]byte, error)
+
+func (f GetterFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
+
+// ProtoGetter fetches a key of a group from a remote peer
+type ProtoGetter interface {
+	Get(ctx context.Context, group, key string) ([]byte, error)
+}
+
+// PeerPicker chooses the peer owning a key, ok is false when the local peer owns it
+type PeerPicker interface {
+	PickPeer(key string) (peer ProtoGetter, ok bool)
+}
+
+// hotCacheRatio is the part of the cache budget given to values owned by other peers
+const hotCacheRatio = 8
+
+var ErrNoGetter = errors.New("peer: nil Getter")
+
+// Stats counts the requests served by a group, all fields are updated atomically
+type Stats struct {
+	Gets           int64 // calls of Get, including requests from peers
+	CacheHits      int64 // served by the main or the hot cache
+	Loads          int64 // cache misses, before deduplication
+	LoadsDeduped   int64 // cache misses which ran a load, after deduplication
+	PeerLoads      int64 // values fetched from the owning peer
+	PeerErrors     int64 // failed fetches from peers, followed by a local load
+	LocalLoads     int64 // values loaded by the Getter
+	LocalLoadErrs  int64 // failed Getter calls
+	ServerRequests int64 // requests received from other peers
+}
+
+type Group struct {
+	name       string
+	cacheBytes uint
+	getter     Getter
+	peers      PeerPicker
+	mainCache  *lru.SyncCache // keys owned by this peer
+	hotCache   *lru.SyncCache // keys owned by other peers
+	loads      flightGroup
+	stats      Stats
+}
+
+func weigh(key lru.Key, value interface{}) uint {
+	return uint(len(key.(string)) + len(value.([]byte)))
+}
+
+// NewGroup returns a group which caches at most cacheBytes bytes of keys and values, and loads misses with getter
+// the group runs on its own until peers are registered with RegisterPeers, see also HTTPPool.NewGroup
+func NewGroup(name string, cacheBytes uint, getter Getter) *Group {
+	if getter == nil {
+		panic(ErrNoGetter)
+	}
+	hotBytes := cacheBytes / hotCacheRatio
+	return &Group{
+		name:       name,
+		cacheBytes: cacheBytes,
+		getter:     getter,
+		mainCache:  lru.NewSyncWeighted(cacheBytes-hotBytes, weigh),
+		hotCache:   lru.NewSyncWeighted(hotBytes, weigh),
+	}
+}
+
+// RegisterPeers makes the group ask peers for the keys it does not own, it must be called before the first Get
+func (g *Group) RegisterPeers(peers PeerPicker) {
+	if g.peers != nil {
+		panic("peer: RegisterPeers called more than once")
+	}
+	g.peers = peers
+}
+
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Stats returns a snapshot of the counters of the group
+func (g *Group) Stats() Stats {
+	return Stats{
+		Gets:           atomic.LoadInt64(&g.stats.Gets),
+		CacheHits:      atomic.LoadInt64(&g.stats.CacheHits),
+		Loads:          atomic.LoadInt64(&g.stats.Loads),
+		LoadsDeduped:   atomic.LoadInt64(&g.stats.LoadsDeduped),
+		PeerLoads:      atomic.LoadInt64(&g.stats.PeerLoads),
+		PeerErrors:     atomic.LoadInt64(&g.stats.PeerErrors),
+		LocalLoads:     atomic.LoadInt64(&g.stats.LocalLoads),
+		LocalLoadErrs:  atomic.LoadInt64(&g.stats.LocalLoadErrs),
+		ServerRequests: atomic.LoadInt64(&g.stats.ServerRequests),
+	}
+}
+
+// Get returns the value of key, from the local caches, from the owning peer or from the Getter
+// the returned slice is a copy and may be modified by the caller
+func (g *Group) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := g.get(ctx, key, true)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// get returns a shared slice which must not be modified
+// a request from another peer passes askPeers = false, so that peers with different views of the ring can not forward a key in circles
+func (g *Group) get(ctx context.Context, key string, askPeers bool) ([]byte, error) {
+	atomic.AddInt64(&g.stats.Gets, 1)
+	if value, hit := g.lookup(key); hit {
+		atomic.AddInt64(&g.stats.CacheHits, 1)
+		return value, nil
+	}
+	return g.load(ctx, key, askPeers)
+}
+
+func (g *Group) lookup(key string) ([]byte, bool) {
+	if value, hit := g.mainCache.Get(key); hit {
+		return value.([]byte), true
+	}
+	if value, hit := g.hotCache.Get(key); hit {
+		return value.([]byte), true
+	}
+	return nil, false
+}
+
+func (g *Group) load(ctx context.Context, key string, askPeers bool) ([]byte, error) {
+	atomic.AddInt64(&g.stats.Loads, 1)
+	value, err, _ := g.loads.do(key, func() ([]byte, error) {
+		// a load for the same key may have finished between the cache miss and this call
+		if value, hit := g.lookup(key); hit {
+			atomic.AddInt64(&g.stats.CacheHits, 1)
+			return value, nil
+		}
+		atomic.AddInt64(&g.stats.LoadsDeduped, 1)
+
+		if askPeers && g.peers != nil {
+			if peer, ok := g.peers.PickPeer(key); ok {
+				value, err := peer.Get(ctx, g.name, key)
+				if err == nil {
+					atomic.AddInt64(&g.stats.PeerLoads, 1)
+					g.hotCache.Put(key, value)
+					return value, nil
+				}
+				// the owner is unreachable, serve the key locally instead of failing
+				atomic.AddInt64(&g.stats.PeerErrors, 1)
+			}
+		}
+
+		value, err := g.getter.Get(ctx, key)
+		if err != nil {
+			atomic.AddInt64(&g.stats.LocalLoadErrs, 1)
+			return nil, err
+		}
+		atomic.AddInt64(&g.stats.LocalLoads, 1)
+		value = append([]byte(nil), value...)
+		g.mainCache.Put(key, value)
+		return value, nil
+	})
+	return value, err
+}
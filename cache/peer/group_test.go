@@ -0,0 +1,274 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPeer struct {
+	url   string
+	pool  *HTTPPool
+	group *Group
+	srv   *http.Server
+
+	mu    sync.Mutex
+	loads map[string]int
+}
+
+func (p *testPeer) Get(ctx context.Context, key string) ([]byte, error) {
+	p.mu.Lock()
+	p.loads[key]++
+	p.mu.Unlock()
+	if key == "bad" {
+		return nil, errors.New("bad key")
+	}
+	if key == "huge" {
+		return make([]byte, 2<<20), nil
+	}
+	return []byte("value of " + key), nil
+}
+
+func (p *testPeer) loaded(key string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loads[key]
+}
+
+// startPeers runs n peers on loopback ports, each serving group "test" through its own HTTPPool
+func startPeers(t *testing.T, n int, getter func(p *testPeer) Getter) []*testPeer {
+	peers := make([]*testPeer, n)
+	urls := make([]string, n)
+	for i := range peers {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		p := &testPeer{
+			url:   "http://" + ln.Addr().String(),
+			loads: make(map[string]int),
+		}
+		p.pool = NewHTTPPool(p.url)
+		p.group = p.pool.NewGroup("test", 1<<20, getter(p))
+		p.srv = &http.Server{Handler: p.pool}
+		go p.srv.Serve(ln)
+		t.Cleanup(func() {
+			p.srv.Close()
+		})
+		peers[i] = p
+		urls[i] = p.url
+	}
+	for _, p := range peers {
+		p.pool.Set(urls...)
+	}
+	return peers
+}
+
+func defaultGetter(p *testPeer) Getter {
+	return p
+}
+
+// owner returns the peer which owns key
+func owner(peers []*testPeer, key string) *testPeer {
+	for _, p := range peers {
+		if _, remote := p.pool.PickPeer(key); !remote {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestGroup_Local(t *testing.T) {
+	loads := 0
+	g := NewGroup("local", 1<<10, GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		loads++
+		return []byte(key + key), nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		value, err := g.Get(context.Background(), "ab")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abab"), value)
+	}
+	assert.Equal(t, 1, loads)
+
+	// callers get copies, so they can not corrupt the cache
+	value, _ := g.Get(context.Background(), "ab")
+	value[0] = 'x'
+	value, _ = g.Get(context.Background(), "ab")
+	assert.Equal(t, []byte("abab"), value)
+
+	st := g.Stats()
+	assert.Equal(t, int64(5), st.Gets)
+	assert.Equal(t, int64(4), st.CacheHits)
+	assert.Equal(t, int64(1), st.LocalLoads)
+	assert.Zero(t, st.PeerLoads)
+}
+
+func TestGroup_OwnerLoadsOnce(t *testing.T) {
+	peers := startPeers(t, 3, defaultGetter)
+	ctx := context.Background()
+
+	keys := make([]string, 30)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	for round := 0; round < 2; round++ {
+		for _, p := range peers {
+			for _, key := range keys {
+				value, err := p.group.Get(ctx, key)
+				require.NoError(t, err)
+				assert.Equal(t, "value of "+key, string(value))
+			}
+		}
+	}
+
+	owned := make(map[*testPeer]int)
+	for _, key := range keys {
+		o := owner(peers, key)
+		require.NotNil(t, o, key)
+		owned[o]++
+		for _, p := range peers {
+			if p == o {
+				assert.Equal(t, 1, p.loaded(key), key)
+			} else {
+				assert.Zero(t, p.loaded(key), key)
+			}
+		}
+	}
+	assert.Len(t, owned, 3, "every peer owns some keys")
+
+	for _, p := range peers {
+		st := p.group.Stats()
+		remote := int64(len(keys) - owned[p])
+		// the second round is served by the main cache and the hot cache
+		assert.Equal(t, remote, st.PeerLoads)
+		assert.Equal(t, int64(owned[p]), st.LocalLoads)
+		// each of the two other peers asked once for every key owned by p
+		assert.Equal(t, int64(2*owned[p]), st.ServerRequests)
+		assert.Equal(t, int64(2*len(keys))+st.ServerRequests, st.Gets)
+	}
+}
+
+func TestGroup_SingleFlight(t *testing.T) {
+	release := make(chan struct{})
+	peers := startPeers(t, 2, func(p *testPeer) Getter {
+		return GetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+			<-release
+			return p.Get(ctx, key)
+		})
+	})
+	key := "hot-key"
+	o := owner(peers, key)
+	other := peers[0]
+	if other == o {
+		other = peers[1]
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for _, p := range peers {
+			wg.Add(1)
+			go func(p *testPeer) {
+				defer wg.Done()
+				value, err := p.group.Get(context.Background(), key)
+				assert.NoError(t, err)
+				assert.Equal(t, "value of "+key, string(value))
+			}(p)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, o.loaded(key))
+	assert.Zero(t, other.loaded(key))
+	assert.Equal(t, int64(1), other.group.Stats().PeerLoads)
+	assert.Equal(t, int64(1), o.group.Stats().ServerRequests)
+}
+
+func TestGroup_PeerDown(t *testing.T) {
+	peers := startPeers(t, 2, defaultGetter)
+	key := "some-key"
+	o := owner(peers, key)
+	other := peers[0]
+	if other == o {
+		other = peers[1]
+	}
+	o.srv.Close()
+
+	value, err := other.group.Get(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "value of "+key, string(value))
+	assert.Equal(t, 1, other.loaded(key))
+	st := other.group.Stats()
+	assert.Equal(t, int64(1), st.PeerErrors)
+	assert.Equal(t, int64(1), st.LocalLoads)
+}
+
+func TestGroup_LoadError(t *testing.T) {
+	peers := startPeers(t, 2, defaultGetter)
+	o := owner(peers, "bad")
+	other := peers[0]
+	if other == o {
+		other = peers[1]
+	}
+
+	_, err := other.group.Get(context.Background(), "bad")
+	assert.EqualError(t, err, "bad key")
+	// the owner failed, then the asking peer tried on its own
+	assert.Equal(t, 1, o.loaded("bad"))
+	assert.Equal(t, 1, other.loaded("bad"))
+	assert.Equal(t, int64(1), other.group.Stats().PeerErrors)
+
+	// errors are not cached
+	_, err = o.group.Get(context.Background(), "bad")
+	assert.Error(t, err)
+	assert.Equal(t, 2, o.loaded("bad"))
+}
+
+// a value beyond the budget of the group is not taken from a peer, the asking peer loads it on its own
+func TestGroup_PeerResponseLimit(t *testing.T) {
+	peers := startPeers(t, 2, defaultGetter)
+	o := owner(peers, "huge")
+	other := peers[0]
+	if other == o {
+		other = peers[1]
+	}
+
+	value, err := other.group.Get(context.Background(), "huge")
+	require.NoError(t, err)
+	assert.Len(t, value, 2<<20)
+	assert.Equal(t, 1, o.loaded("huge"))
+	assert.Equal(t, 1, other.loaded("huge"))
+	st := other.group.Stats()
+	assert.Equal(t, int64(1), st.PeerErrors)
+	assert.Zero(t, st.PeerLoads)
+}
+
+func TestHTTPPool_ServeHTTP(t *testing.T) {
+	peers := startPeers(t, 1, defaultGetter)
+	p := peers[0]
+
+	res, err := http.Get(p.url + DefaultBasePath + "test/a%2Fb")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 1, p.loaded("a/b"))
+
+	res, err = http.Get(p.url + DefaultBasePath + "missing/a")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res, err = http.Get(p.url + "/elsewhere")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
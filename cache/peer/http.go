@@ -0,0 +1,157 @@
+package peer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	DefaultBasePath = "/_dsgym/"
+	DefaultReplicas = 50
+)
+
+// HTTPPool is a set of peers talking over HTTP, it serves the groups of the local peer and picks peers for them
+// a request for key k of group g is a GET of <peer><BasePath><g>/<k>
+type HTTPPool struct {
+	self     string
+	basePath string
+
+	// Client sends the requests to other peers, http.DefaultClient is used when it is nil
+	Client *http.Client
+
+	mu      sync.Mutex
+	ring    *Ring
+	getters map[string]*httpGetter
+	groups  map[string]*Group
+}
+
+// NewHTTPPool returns a pool for the local peer whose base URL is self, e.g. "http://10.0.0.1:8000"
+func NewHTTPPool(self string) *HTTPPool {
+	return &HTTPPool{
+		self:     self,
+		basePath: DefaultBasePath,
+		ring:     NewRing(DefaultReplicas, nil),
+		getters:  make(map[string]*httpGetter),
+		groups:   make(map[string]*Group),
+	}
+}
+
+// Set replaces the peers of the pool, each peer is a base URL like self, which should be one of them
+func (p *HTTPPool) Set(peers ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ring = NewRing(DefaultReplicas, nil)
+	p.ring.Add(peers...)
+	p.getters = make(map[string]*httpGetter, len(peers))
+	for _, peer := range peers {
+		p.getters[peer] = &httpGetter{pool: p, baseURL: peer + p.basePath}
+	}
+}
+
+// PickPeer returns the owner of key, ok is false when the local peer owns it or no peers are set
+func (p *HTTPPool) PickPeer(key string) (ProtoGetter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	peer := p.ring.Get(key)
+	if peer == "" || peer == p.self {
+		return nil, false
+	}
+	return p.getters[peer], true
+}
+
+// NewGroup creates a group served by the pool, whose keys are spread over the peers of the pool
+func (p *HTTPPool) NewGroup(name string, cacheBytes uint, getter Getter) *Group {
+	g := NewGroup(name, cacheBytes, getter)
+	g.RegisterPeers(p)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, dup := p.groups[name]; dup {
+		panic("peer: duplicate group " + name)
+	}
+	p.groups[name] = g
+	return g
+}
+
+// Group returns the group called name, or nil if it does not exist
+func (p *HTTPPool) Group(name string) *Group {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.groups[name]
+}
+
+func (p *HTTPPool) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, p.basePath) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.SplitN(r.URL.Path[len(p.basePath):], "/", 2)
+	if len(parts) != 2 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	g := p.Group(parts[0])
+	if g == nil {
+		http.Error(w, "no such group: "+parts[0], http.StatusNotFound)
+		return
+	}
+
+	atomic.AddInt64(&g.stats.ServerRequests, 1)
+	value, err := g.get(r.Context(), parts[1], false)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(value)
+}
+
+type httpGetter struct {
+	pool    *HTTPPool
+	baseURL string
+}
+
+// Get fetches the value of key from the peer, a value larger than the byte budget of the group is refused
+func (h *httpGetter) Get(ctx context.Context, group, key string) ([]byte, error) {
+	g := h.pool.Group(group)
+	if g == nil {
+		return nil, fmt.Errorf("peer: group %s is not served by the pool", group)
+	}
+	limit := int64(g.cacheBytes)
+	u := h.baseURL + url.PathEscape(group) + "/" + url.PathEscape(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := h.pool.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer: %s returned %s: %s", u, res.Status, strings.TrimSpace(string(body)))
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("peer: %s returned more than %d bytes", u, limit)
+	}
+	return body, nil
+}
@@ -0,0 +1,98 @@
+package peer
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Hash maps bytes to a point on the ring
+type Hash func(data []byte) uint32
+
+// Ring is a consistent hash ring, every node is placed on the ring replicas times
+// a key belongs to the first virtual node clockwise from its hash
+// so adding or removing a node only moves the keys of that node
+// a Ring is not safe for concurrent use
+type Ring struct {
+	hash     Hash
+	replicas int
+	nodes    map[string]struct{}
+	points   []uint32 // sorted
+	owners   map[uint32]string
+}
+
+// NewRing returns an empty ring which places each node replicas times, a nil hash means crc32
+func NewRing(replicas int, hash Hash) *Ring {
+	if replicas < 1 {
+		replicas = 1
+	}
+	if hash == nil {
+		hash = crc32.ChecksumIEEE
+	}
+	return &Ring{
+		hash:     hash,
+		replicas: replicas,
+		nodes:    make(map[string]struct{}),
+		owners:   make(map[uint32]string),
+	}
+}
+
+// IsEmpty returns whether no node is on the ring
+func (r *Ring) IsEmpty() bool {
+	return len(r.points) == 0
+}
+
+func (r *Ring) virtualNode(node string, i int) uint32 {
+	return r.hash([]byte(strconv.Itoa(i) + node))
+}
+
+// Add places nodes on the ring, nodes which are already on it are not added twice
+func (r *Ring) Add(nodes ...string) {
+	for _, node := range nodes {
+		r.nodes[node] = struct{}{}
+	}
+	r.rebuild()
+}
+
+// Remove takes node off the ring
+func (r *Ring) Remove(node string) {
+	delete(r.nodes, node)
+	r.rebuild()
+}
+
+func (r *Ring) rebuild() {
+	r.points = r.points[:0]
+	r.owners = make(map[uint32]string, len(r.nodes)*r.replicas)
+	for node := range r.nodes {
+		for i := 0; i < r.replicas; i++ {
+			h := r.virtualNode(node, i)
+			if owner, taken := r.owners[h]; taken {
+				// on a collision the smaller node keeps the point, so the ring does not depend on map order
+				if node < owner {
+					r.owners[h] = node
+				}
+				continue
+			}
+			r.owners[h] = node
+			r.points = append(r.points, h)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i] < r.points[j]
+	})
+}
+
+// Get returns the node owning key, an empty string is returned when the ring is empty
+func (r *Ring) Get(key string) string {
+	if r.IsEmpty() {
+		return ""
+	}
+	h := r.hash([]byte(key))
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
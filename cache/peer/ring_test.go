@@ -0,0 +1,79 @@
+package peer
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// atoiHash places "<i><node>" at i*10+node, so the points on the ring are easy to predict
+func atoiHash(data []byte) uint32 {
+	n, err := strconv.Atoi(string(data))
+	if err != nil {
+		panic(err)
+	}
+	return uint32(n)
+}
+
+func TestRing_Get(t *testing.T) {
+	r := NewRing(3, atoiHash)
+	assert.True(t, r.IsEmpty())
+	assert.Equal(t, "", r.Get("1"))
+
+	// points: 2, 4, 6, 12, 14, 16, 22, 24, 26
+	r.Add("6", "4", "2")
+	cases := map[string]string{
+		"2":  "2",
+		"11": "2",
+		"23": "4",
+		"27": "2",
+		"0":  "2",
+		"15": "6",
+	}
+	for key, node := range cases {
+		assert.Equal(t, node, r.Get(key), key)
+	}
+
+	// points 8, 18, 28 take over the keys wrapping around from 26
+	r.Add("8")
+	cases["27"] = "8"
+	for key, node := range cases {
+		assert.Equal(t, node, r.Get(key), key)
+	}
+
+	r.Remove("8")
+	cases["27"] = "2"
+	for key, node := range cases {
+		assert.Equal(t, node, r.Get(key), key)
+	}
+}
+
+func TestRing_Consistency(t *testing.T) {
+	r1 := NewRing(50, nil)
+	r2 := NewRing(50, nil)
+	r1.Add("a", "b", "c")
+	r2.Add("c", "a", "b")
+
+	owners := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		assert.Equal(t, r1.Get(key), r2.Get(key))
+		owners[key] = r1.Get(key)
+	}
+
+	// removing a node only moves its own keys
+	r1.Remove("b")
+	moved := 0
+	for key, owner := range owners {
+		if owner == "b" {
+			assert.NotEqual(t, "b", r1.Get(key))
+			moved++
+		} else {
+			assert.Equal(t, owner, r1.Get(key))
+		}
+	}
+	// with virtual nodes every node owns a fair share of the keys
+	assert.InDelta(t, 333, moved, 150)
+}
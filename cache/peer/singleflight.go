@@ -0,0 +1,56 @@
+package peer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrLoadPanicked is returned to the callers which waited for a load that panicked, the caller running the load panics again
+var ErrLoadPanicked = errors.New("peer: load panicked")
+
+type call struct {
+	wg    sync.WaitGroup
+	value []byte
+	err   error
+}
+
+// flightGroup makes concurrent loads of the same key share one execution
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do runs fn for key unless a call for key is in flight, in which case it waits for that call and returns its result
+// shared reports whether the result came from another caller's execution
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) (value []byte, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err, true
+	}
+	c := &call{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			c.value, c.err = nil, fmt.Errorf("%w: %v", ErrLoadPanicked, r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+		if r != nil {
+			panic(r)
+		}
+	}()
+	c.value, c.err = fn()
+	return c.value, c.err, false
+}
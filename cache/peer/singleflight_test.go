@@ -0,0 +1,112 @@
+package peer
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightGroup_Do(t *testing.T) {
+	var g flightGroup
+	value, err, shared := g.do("key", func() ([]byte, error) {
+		return []byte("value"), nil
+	})
+	assert.Equal(t, []byte("value"), value)
+	assert.NoError(t, err)
+	assert.False(t, shared)
+
+	boom := errors.New("boom")
+	_, err, _ = g.do("key", func() ([]byte, error) {
+		return nil, boom
+	})
+	assert.Equal(t, boom, err)
+}
+
+func TestFlightGroup_Dedup(t *testing.T) {
+	var g flightGroup
+	var calls int32
+	release := make(chan struct{})
+
+	fn := func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []byte("v"), nil
+	}
+
+	var wg sync.WaitGroup
+	var shared int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err, s := g.do("key", fn)
+			assert.Equal(t, []byte("v"), value)
+			assert.NoError(t, err)
+			if s {
+				atomic.AddInt32(&shared, 1)
+			}
+		}()
+	}
+	// the waiters can not be observed directly, give them time to join the call
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(9), atomic.LoadInt32(&shared))
+}
+
+func TestFlightGroup_Panic(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer func() {
+			assert.Equal(t, "boom", recover())
+			close(done)
+		}()
+		g.do("key", func() ([]byte, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	var wg sync.WaitGroup
+	var shared int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err, s := g.do("key", func() ([]byte, error) {
+				return []byte("not shared"), nil
+			})
+			assert.Nil(t, value)
+			assert.ErrorIs(t, err, ErrLoadPanicked)
+			if s {
+				atomic.AddInt32(&shared, 1)
+			}
+		}()
+	}
+	// the waiters can not be observed directly, give them time to join the call
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	<-done
+	assert.Equal(t, int32(5), atomic.LoadInt32(&shared))
+
+	// the call is forgotten, so the key can be loaded again
+	value, err, s := g.do("key", func() ([]byte, error) {
+		return []byte("v"), nil
+	})
+	assert.Equal(t, []byte("v"), value)
+	assert.NoError(t, err)
+	assert.False(t, s)
+}
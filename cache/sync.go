@@ -0,0 +1,76 @@
+package lru
+
+import "sync"
+
+// SyncCache is an LRUCache guarded by a mutex, it is safe for concurrent use
+type SyncCache struct {
+	mu    sync.Mutex
+	cache LRUCache
+}
+
+// NewSync returns a SyncCache which holds at most cap entries
+func NewSync(cap uint) *SyncCache {
+	return NewSyncWeighted(cap, nil)
+}
+
+// NewSyncWeighted returns a SyncCache whose entries cost at most cap in total, see NewWeighted
+func NewSyncWeighted(cap uint, w Weigher) *SyncCache {
+	return &SyncCache{cache: NewWeighted(cap, w)}
+}
+
+// OnEvict registers f to be called with every evicted entry, f is called with the lock held
+func (c *SyncCache) OnEvict(f func(key Key, value interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.OnEvict(f)
+}
+
+func (c *SyncCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Clear()
+}
+
+func (c *SyncCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Len()
+}
+
+func (c *SyncCache) Weight() uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Weight()
+}
+
+func (c *SyncCache) Get(key Key) (result interface{}, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Get(key)
+}
+
+func (c *SyncCache) Put(key Key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Put(key, value)
+}
+
+func (c *SyncCache) Remove(key Key) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Remove(key)
+}
+
+// GetMany is LRUCache.GetMany under a single lock, so the recency updates of the batch are not interleaved with other calls
+func (c *SyncCache) GetMany(keys []Key) (values []interface{}, hits []bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.GetMany(keys)
+}
+
+// PutMany is LRUCache.PutMany under a single lock
+func (c *SyncCache) PutMany(entries []Entry) (updated []bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.PutMany(entries)
+}
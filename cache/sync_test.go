@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncCache_Concurrent(t *testing.T) {
+	cache := NewSync(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := (i*1000 + j) % 150
+				cache.Put(key, j)
+				cache.Get(key)
+				if j%10 == 0 {
+					cache.Remove(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, cache.Len(), 100)
+	assert.Equal(t, uint(cache.Len()), cache.Weight())
+}
+
+func TestSyncCache_Many(t *testing.T) {
+	cache := NewSync(10)
+	assert.Equal(t, []bool{false, false}, cache.PutMany([]Entry{{"a", 1}, {"b", 2}}))
+	assert.Equal(t, []bool{true, false}, cache.PutMany([]Entry{{"b", 2}, {"c", 3}}))
+	values, hits := cache.GetMany([]Key{"a", "x", "b"})
+	assert.Equal(t, []interface{}{1, nil, 2}, values)
+	assert.Equal(t, []bool{true, false, true}, hits)
+}
+
+func benchmarkKeys(n int) []Key {
+	keys := make([]Key, n)
+	for i := range keys {
+		keys[i] = i * 7 % 1000
+	}
+	return keys
+}
+
+func BenchmarkSyncCache_Get(b *testing.B) {
+	cache := NewSync(1000)
+	for i := 0; i < 1000; i++ {
+		cache.Put(i, i)
+	}
+	keys := benchmarkKeys(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			cache.Get(key)
+		}
+	}
+}
+
+func BenchmarkSyncCache_GetMany(b *testing.B) {
+	cache := NewSync(1000)
+	for i := 0; i < 1000; i++ {
+		cache.Put(i, i)
+	}
+	keys := benchmarkKeys(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.GetMany(keys)
+	}
+}
@@ -0,0 +1,251 @@
+package heap
+
+import "github.com/derekcdz/dsgym/internal/ownership"
+
+// FibNode designates a value inserted into a FibonacciHeap, it is the way to decrease the value later
+type FibNode[T any] struct {
+	value  T
+	parent *FibNode[T]
+	child  *FibNode[T] // any of the children, they form a circular list through left and right
+	left   *FibNode[T]
+	right  *FibNode[T]
+	degree int  // number of children
+	marked bool // whether the node lost a child since it became a child itself
+
+	belongsTo *ownership.Token[FibonacciHeap[T]] // merged by Meld
+}
+
+func (n *FibNode[T]) Value() T {
+	return n.value
+}
+
+// FibonacciHeap is a circular list of heap-ordered trees with O(1) Push and Meld, and amortized O(1) DecreaseKey
+// the work is put off until Pop, which links the trees of equal degree in amortized O(logN)
+// it has the best bounds of the package, but larger constants than PairingHeap, which is faster in the benchmarks
+// the zero value is not usable, a FibonacciHeap must be created with NewFibonacci
+type FibonacciHeap[T any] struct {
+	min   *FibNode[T] // the root which comes first, nil when empty
+	len   int
+	cmp   func(a, b T) int
+	owner *ownership.Token[FibonacciHeap[T]]
+
+	byDegree []*FibNode[T] // reused by consolidate
+	roots    []*FibNode[T]
+}
+
+// NewFibonacci returns an empty Fibonacci heap ordered by cmp
+func NewFibonacci[T any](cmp func(a, b T) int) *FibonacciHeap[T] {
+	h := &FibonacciHeap[T]{cmp: cmp}
+	h.owner = ownership.New(h)
+	return h
+}
+
+func (h *FibonacciHeap[T]) Len() int {
+	return h.len
+}
+
+// Contains returns whether n designates a value in the heap
+func (h *FibonacciHeap[T]) Contains(n *FibNode[T]) bool {
+	return n != nil && n.belongsTo.Owner() == h
+}
+
+// owns is Contains for the methods which modify h, it also shortens the way from n to the owner of h
+func (h *FibonacciHeap[T]) owns(n *FibNode[T]) bool {
+	if !h.Contains(n) {
+		return false
+	}
+	n.belongsTo.Compress()
+	n.belongsTo = h.owner
+	return true
+}
+
+func (h *FibonacciHeap[T]) less(a, b *FibNode[T]) bool {
+	return h.cmp(a.value, b.value) < 0
+}
+
+// splice joins the circular lists of a and b into one
+func splice[T any](a, b *FibNode[T]) {
+	aRight, bLeft := a.right, b.left
+	a.right = b
+	b.left = a
+	bLeft.right = aRight
+	aRight.left = bLeft
+}
+
+// addRoot puts the detached tree n in the root list
+func (h *FibonacciHeap[T]) addRoot(n *FibNode[T]) {
+	n.left, n.right = n, n
+	n.parent = nil
+	if h.min == nil {
+		h.min = n
+		return
+	}
+	splice(h.min, n)
+	if h.less(n, h.min) {
+		h.min = n
+	}
+}
+
+// Push adds v in O(1)
+func (h *FibonacciHeap[T]) Push(v T) {
+	h.Insert(v)
+}
+
+// Insert adds v in O(1) and returns its node, for DecreaseKey
+func (h *FibonacciHeap[T]) Insert(v T) *FibNode[T] {
+	n := &FibNode[T]{value: v, belongsTo: h.owner}
+	h.addRoot(n)
+	h.len++
+	return n
+}
+
+// Peek returns the first value without removing it, ok is false when the heap is empty
+func (h *FibonacciHeap[T]) Peek() (v T, ok bool) {
+	if h.min == nil {
+		return v, false
+	}
+	return h.min.value, true
+}
+
+// Pop removes and returns the first value in amortized O(logN), ok is false when the heap is empty
+func (h *FibonacciHeap[T]) Pop() (v T, ok bool) {
+	z := h.min
+	if z == nil {
+		return v, false
+	}
+	// the children of z become roots
+	if c := z.child; c != nil {
+		for x := c; ; {
+			x.parent = nil
+			x = x.right
+			if x == c {
+				break
+			}
+		}
+		splice(z, c)
+	}
+	if z.right == z {
+		h.min = nil
+	} else {
+		z.left.right = z.right
+		z.right.left = z.left
+		h.min = z.right
+		h.consolidate()
+	}
+	h.len--
+	z.child, z.left, z.right = nil, nil, nil
+	z.belongsTo = nil
+	return z.value, true
+}
+
+// consolidate links the roots until no two have the same degree, then rebuilds the root list and finds the minimum
+func (h *FibonacciHeap[T]) consolidate() {
+	h.roots = h.roots[:0]
+	for x := h.min; ; {
+		h.roots = append(h.roots, x)
+		x = x.right
+		if x == h.min {
+			break
+		}
+	}
+
+	for _, x := range h.roots {
+		d := x.degree
+		for d < len(h.byDegree) && h.byDegree[d] != nil {
+			y := h.byDegree[d]
+			if h.less(y, x) {
+				x, y = y, x
+			}
+			h.link(y, x)
+			h.byDegree[d] = nil
+			d++
+		}
+		for d >= len(h.byDegree) {
+			h.byDegree = append(h.byDegree, nil)
+		}
+		h.byDegree[d] = x
+	}
+
+	h.min = nil
+	for d, x := range h.byDegree {
+		if x != nil {
+			h.addRoot(x)
+			h.byDegree[d] = nil
+		}
+	}
+	clear(h.roots)
+}
+
+// link makes the root y a child of the root x, the root list is rebuilt by consolidate afterwards
+func (h *FibonacciHeap[T]) link(y, x *FibNode[T]) {
+	y.left, y.right = y, y
+	y.parent = x
+	y.marked = false
+	if x.child == nil {
+		x.child = y
+	} else {
+		splice(x.child, y)
+	}
+	x.degree++
+}
+
+// DecreaseKey replaces the value of n with v, which must not come after it, in amortized O(1)
+// it returns false and does nothing when n does not belong to the heap or v comes after the current value
+func (h *FibonacciHeap[T]) DecreaseKey(n *FibNode[T], v T) bool {
+	if !h.owns(n) || h.cmp(v, n.value) > 0 {
+		return false
+	}
+	n.value = v
+	if p := n.parent; p != nil && h.less(n, p) {
+		h.cut(n, p)
+		// a node which loses a second child is cut too, which keeps the trees bushy enough for the O(logN) bound
+		for y, p := p, p.parent; p != nil; y, p = p, p.parent {
+			if !y.marked {
+				y.marked = true
+				break
+			}
+			h.cut(y, p)
+		}
+	}
+	if h.less(n, h.min) {
+		h.min = n
+	}
+	return true
+}
+
+// cut moves x from the children of p to the root list
+func (h *FibonacciHeap[T]) cut(x, p *FibNode[T]) {
+	if x.right == x {
+		p.child = nil
+	} else {
+		if p.child == x {
+			p.child = x.right
+		}
+		x.left.right = x.right
+		x.right.left = x.left
+	}
+	p.degree--
+	x.marked = false
+	h.addRoot(x)
+}
+
+// Meld moves all values of other into h in O(1) and leaves other empty
+// the nodes of other belong to h afterwards
+func (h *FibonacciHeap[T]) Meld(other *FibonacciHeap[T]) {
+	if other == h || other.min == nil {
+		return
+	}
+	if h.min == nil {
+		h.min = other.min
+	} else {
+		splice(h.min, other.min)
+		if h.less(other.min, h.min) {
+			h.min = other.min
+		}
+	}
+	h.len += other.len
+	other.min = nil
+	other.len = 0
+	h.owner = h.owner.Merge(other.owner)
+	other.owner = ownership.New(other)
+}
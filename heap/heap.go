@@ -0,0 +1,142 @@
+// Priority queues, the value ordered first by the comparator is always the next to come out
+// a comparator follows the convention of Key.CompareTo in the trees: cmp(a, b) is negative when a comes before b,
+// positive when a comes after b, and zero when either may come first
+package heap
+
+// Heap is the interface shared by the heaps of the package
+type Heap[T any] interface {
+	Len() int
+	Push(v T)
+	Peek() (T, bool)
+	Pop() (T, bool)
+}
+
+// PriorityQueue is a d-ary heap stored in a slice, a binary heap by default
+// Push and Pop take O(log_d N) sift steps, a wider heap is shallower, so it pushes faster and touches fewer cache lines,
+// but each step of Pop compares d children
+// the zero value is not usable, a PriorityQueue must be created with one of the constructors
+type PriorityQueue[T any] struct {
+	items []T
+	cmp   func(a, b T) int
+	arity int
+}
+
+// New returns an empty binary heap ordered by cmp
+func New[T any](cmp func(a, b T) int) *PriorityQueue[T] {
+	return NewDary(2, cmp)
+}
+
+// NewDary returns an empty heap where each value has up to arity children, an arity below 2 is taken as 2
+func NewDary[T any](arity int, cmp func(a, b T) int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{cmp: cmp, arity: max(arity, 2)}
+}
+
+// From returns a binary heap holding items, it is built in O(N), faster than pushing the items one by one
+// the heap takes items over, the caller must not use the slice afterwards
+func From[T any](items []T, cmp func(a, b T) int) *PriorityQueue[T] {
+	return FromDary(2, items, cmp)
+}
+
+// FromDary is From for a heap with the given arity
+func FromDary[T any](arity int, items []T, cmp func(a, b T) int) *PriorityQueue[T] {
+	pq := NewDary(arity, cmp)
+	pq.items = items
+	// the values from len/d on are leaves, sifting the others down from the last one makes every subtree a heap
+	for i := (len(items) - 2) / pq.arity; i >= 0; i-- {
+		pq.down(i)
+	}
+	return pq
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Push adds v to the heap in O(log N)
+func (pq *PriorityQueue[T]) Push(v T) {
+	pq.items = append(pq.items, v)
+	pq.up(len(pq.items) - 1)
+}
+
+// Peek returns the first value without removing it, ok is false when the heap is empty
+func (pq *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if len(pq.items) == 0 {
+		return v, false
+	}
+	return pq.items[0], true
+}
+
+// Pop removes and returns the first value in O(log N), ok is false when the heap is empty
+func (pq *PriorityQueue[T]) Pop() (v T, ok bool) {
+	n := len(pq.items) - 1
+	if n < 0 {
+		return v, false
+	}
+	v = pq.items[0]
+	pq.items[0] = pq.items[n]
+	var zero T
+	pq.items[n] = zero
+	pq.items = pq.items[:n]
+	if n > 0 {
+		pq.down(0)
+	}
+	return v, true
+}
+
+// Meld moves all values of other into pq and leaves other empty
+// the values are appended and the heap is rebuilt in O(N+M), see PairingHeap and FibonacciHeap for an O(1) meld
+func (pq *PriorityQueue[T]) Meld(other *PriorityQueue[T]) {
+	if other == pq || len(other.items) == 0 {
+		return
+	}
+	pq.items = append(pq.items, other.items...)
+	other.Clear()
+	for i := (len(pq.items) - 2) / pq.arity; i >= 0; i-- {
+		pq.down(i)
+	}
+}
+
+// Clear removes all values
+func (pq *PriorityQueue[T]) Clear() {
+	clear(pq.items)
+	pq.items = pq.items[:0]
+}
+
+// up moves the value at i towards the root while it comes before its parent
+func (pq *PriorityQueue[T]) up(i int) {
+	v := pq.items[i]
+	for i > 0 {
+		parent := (i - 1) / pq.arity
+		if pq.cmp(v, pq.items[parent]) >= 0 {
+			break
+		}
+		pq.items[i] = pq.items[parent]
+		i = parent
+	}
+	pq.items[i] = v
+}
+
+// down moves the value at i towards the leaves while one of its children comes before it
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.items)
+	v := pq.items[i]
+	for {
+		first := pq.arity*i + 1
+		if first >= n {
+			break
+		}
+		// find the child which comes first
+		best := first
+		for c := first + 1; c < min(first+pq.arity, n); c++ {
+			if pq.cmp(pq.items[c], pq.items[best]) < 0 {
+				best = c
+			}
+		}
+		if pq.cmp(pq.items[best], v) >= 0 {
+			break
+		}
+		pq.items[i] = pq.items[best]
+		i = best
+	}
+	pq.items[i] = v
+}
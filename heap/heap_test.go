@@ -0,0 +1,138 @@
+package heap
+
+import (
+	"cmp"
+	stdheap "container/heap"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// isHeap checks that no value comes before its parent
+func isHeap[T any](pq *PriorityQueue[T]) bool {
+	for i := 1; i < len(pq.items); i++ {
+		if pq.cmp(pq.items[i], pq.items[(i-1)/pq.arity]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func drain[T any](pq *PriorityQueue[T]) []T {
+	var res []T
+	for {
+		v, ok := pq.Pop()
+		if !ok {
+			return res
+		}
+		res = append(res, v)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq := New(cmp.Compare[int])
+	_, ok := pq.Pop()
+	assert.False(t, ok)
+	_, ok = pq.Peek()
+	assert.False(t, ok)
+
+	for _, v := range []int{5, 3, 8, 1, 9, 1} {
+		pq.Push(v)
+	}
+	assert.Equal(t, 6, pq.Len())
+	v, ok := pq.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []int{1, 1, 3, 5, 8, 9}, drain(pq))
+	assert.Zero(t, pq.Len())
+
+	// a reversed comparator makes a max-heap
+	pq = New(func(a, b int) int { return b - a })
+	pq.Push(1)
+	pq.Push(3)
+	pq.Push(2)
+	assert.Equal(t, []int{3, 2, 1}, drain(pq))
+
+	pq.Push(4)
+	pq.Clear()
+	assert.Zero(t, pq.Len())
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, arity := range []int{0, 2, 3, 4, 8} {
+		items := r.Perm(500)
+		want := slices.Clone(items)
+		slices.Sort(want)
+
+		pq := FromDary(arity, slices.Clone(items), cmp.Compare[int])
+		assert.True(t, isHeap(pq), "arity %d", arity)
+		assert.Equal(t, want, drain(pq), "arity %d", arity)
+
+		pq = NewDary(arity, cmp.Compare[int])
+		for _, v := range items {
+			pq.Push(v)
+			assert.True(t, isHeap(pq))
+		}
+		assert.Equal(t, want, drain(pq), "arity %d", arity)
+	}
+
+	pq := From([]int{}, cmp.Compare[int])
+	assert.Zero(t, pq.Len())
+	pq = From([]int{2, 1}, cmp.Compare[int])
+	assert.Equal(t, []int{1, 2}, drain(pq))
+}
+
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+const benchSize = 1 << 16
+
+func benchmarkPushPop(b *testing.B, pq *PriorityQueue[int]) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < benchSize; i++ {
+		pq.Push(r.Int())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, _ := pq.Pop()
+		pq.Push(v + r.Intn(benchSize))
+	}
+}
+
+func BenchmarkPriorityQueue_Binary(b *testing.B) {
+	benchmarkPushPop(b, New(cmp.Compare[int]))
+}
+
+func BenchmarkPriorityQueue_4ary(b *testing.B) {
+	benchmarkPushPop(b, NewDary(4, cmp.Compare[int]))
+}
+
+func BenchmarkPriorityQueue_8ary(b *testing.B) {
+	benchmarkPushPop(b, NewDary(8, cmp.Compare[int]))
+}
+
+func BenchmarkContainerHeap(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	h := &intHeap{}
+	for i := 0; i < benchSize; i++ {
+		stdheap.Push(h, r.Int())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v := stdheap.Pop(h).(int)
+		stdheap.Push(h, v+r.Intn(benchSize))
+	}
+}
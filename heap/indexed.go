@@ -0,0 +1,168 @@
+package heap
+
+// ByCompareTo orders keys by their CompareTo method, so the keys of the trees, e.g. rb_tree.Key, can be queued as they are
+func ByCompareTo[K interface{ CompareTo(K) int }](a, b K) int {
+	return a.CompareTo(b)
+}
+
+// Handle designates a value pushed to an IndexedPriorityQueue, it is the way to update or remove the value later
+type Handle[T any] struct {
+	value T
+	index int // position in the heap
+
+	belongsTo *IndexedPriorityQueue[T]
+}
+
+// Value returns the value designated by h, which is kept after h left its queue
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// IndexedPriorityQueue is a binary heap whose values can be updated or removed wherever they are
+// every value knows its position in the heap, so Update and Remove sift it from there in O(logN)
+// like the elements of a list, a handle can only be used with the queue it belongs to,
+// a handle which was popped or removed belongs to no queue and is ignored
+// the zero value is not usable, an IndexedPriorityQueue must be created with NewIndexed
+type IndexedPriorityQueue[T any] struct {
+	items []*Handle[T]
+	cmp   func(a, b T) int
+}
+
+// NewIndexed returns an empty queue ordered by cmp
+func NewIndexed[T any](cmp func(a, b T) int) *IndexedPriorityQueue[T] {
+	return &IndexedPriorityQueue[T]{cmp: cmp}
+}
+
+func (pq *IndexedPriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Contains returns whether h designates a value in the queue
+func (pq *IndexedPriorityQueue[T]) Contains(h *Handle[T]) bool {
+	return h != nil && h.belongsTo == pq
+}
+
+// Push adds v in O(logN) and returns its handle
+func (pq *IndexedPriorityQueue[T]) Push(v T) *Handle[T] {
+	h := &Handle[T]{value: v, index: len(pq.items), belongsTo: pq}
+	pq.items = append(pq.items, h)
+	pq.up(h.index)
+	return h
+}
+
+// Peek returns the first value without removing it, ok is false when the queue is empty
+func (pq *IndexedPriorityQueue[T]) Peek() (v T, ok bool) {
+	if h := pq.PeekHandle(); h != nil {
+		return h.value, true
+	}
+	return v, false
+}
+
+// PeekHandle returns the handle of the first value, nil when the queue is empty
+func (pq *IndexedPriorityQueue[T]) PeekHandle() *Handle[T] {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	return pq.items[0]
+}
+
+// Pop removes and returns the first value in O(logN), ok is false when the queue is empty
+func (pq *IndexedPriorityQueue[T]) Pop() (v T, ok bool) {
+	if len(pq.items) == 0 {
+		return v, false
+	}
+	return pq.remove(0).value, true
+}
+
+// Update replaces the value designated by h with v and moves it to its new place in O(logN)
+// it returns false and does nothing when h does not belong to the queue
+func (pq *IndexedPriorityQueue[T]) Update(h *Handle[T], v T) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	h.value = v
+	pq.fix(h.index)
+	return true
+}
+
+// Remove removes the value designated by h in O(logN)
+// it returns false and does nothing when h does not belong to the queue
+func (pq *IndexedPriorityQueue[T]) Remove(h *Handle[T]) bool {
+	if !pq.Contains(h) {
+		return false
+	}
+	pq.remove(h.index)
+	return true
+}
+
+// Clear removes all values, their handles no longer belong to the queue
+func (pq *IndexedPriorityQueue[T]) Clear() {
+	for _, h := range pq.items {
+		h.belongsTo = nil
+	}
+	clear(pq.items)
+	pq.items = pq.items[:0]
+}
+
+// remove takes out the value at i, the last value fills the hole and is sifted from there
+func (pq *IndexedPriorityQueue[T]) remove(i int) *Handle[T] {
+	h := pq.items[i]
+	n := len(pq.items) - 1
+	pq.swap(i, n)
+	pq.items[n] = nil
+	pq.items = pq.items[:n]
+	if i < n {
+		pq.fix(i)
+	}
+	h.belongsTo = nil
+	return h
+}
+
+func (pq *IndexedPriorityQueue[T]) less(i, j int) bool {
+	return pq.cmp(pq.items[i].value, pq.items[j].value) < 0
+}
+
+func (pq *IndexedPriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+// fix restores the heap after the value at i changed, it moves either up or down
+func (pq *IndexedPriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *IndexedPriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down returns whether the value at i moved
+func (pq *IndexedPriorityQueue[T]) down(i int) bool {
+	start := i
+	n := len(pq.items)
+	for {
+		c := 2*i + 1
+		if c >= n {
+			break
+		}
+		if c+1 < n && pq.less(c+1, c) {
+			c++
+		}
+		if !pq.less(c, i) {
+			break
+		}
+		pq.swap(i, c)
+		i = c
+	}
+	return i > start
+}
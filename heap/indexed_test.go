@@ -0,0 +1,120 @@
+package heap
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func isIndexedHeap[T any](t *testing.T, pq *IndexedPriorityQueue[T]) {
+	for i, h := range pq.items {
+		assert.Equal(t, i, h.index)
+		assert.Same(t, pq, h.belongsTo)
+		if i > 0 {
+			assert.False(t, pq.less(i, (i-1)/2))
+		}
+	}
+}
+
+func TestIndexedPriorityQueue(t *testing.T) {
+	pq := NewIndexed(cmp.Compare[int])
+	_, ok := pq.Pop()
+	assert.False(t, ok)
+	assert.Nil(t, pq.PeekHandle())
+
+	handles := map[int]*Handle[int]{}
+	for _, v := range []int{50, 30, 80, 10, 90} {
+		handles[v] = pq.Push(v)
+	}
+	isIndexedHeap(t, pq)
+	assert.Same(t, handles[10], pq.PeekHandle())
+
+	// decrease-key and increase-key
+	assert.True(t, pq.Update(handles[80], 5))
+	assert.True(t, pq.Update(handles[10], 60))
+	isIndexedHeap(t, pq)
+	v, _ := pq.Peek()
+	assert.Equal(t, 5, v)
+	assert.Equal(t, 5, handles[80].Value())
+
+	assert.True(t, pq.Remove(handles[30]))
+	assert.False(t, pq.Contains(handles[30]))
+	assert.False(t, pq.Remove(handles[30]))
+	assert.False(t, pq.Update(handles[30], 0))
+	assert.Equal(t, 30, handles[30].Value())
+	isIndexedHeap(t, pq)
+
+	v, _ = pq.Pop()
+	assert.Equal(t, 5, v)
+	assert.False(t, pq.Contains(handles[80]), "a popped handle leaves the queue")
+	assert.Equal(t, 3, pq.Len())
+
+	// a handle of another queue is rejected
+	other := NewIndexed(cmp.Compare[int])
+	foreign := other.Push(1)
+	assert.False(t, pq.Contains(foreign))
+	assert.False(t, pq.Update(foreign, 0))
+	assert.False(t, pq.Remove(foreign))
+	assert.False(t, pq.Contains(nil))
+
+	pq.Clear()
+	assert.Zero(t, pq.Len())
+	assert.False(t, pq.Contains(handles[50]))
+}
+
+func TestIndexedPriorityQueue_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pq := NewIndexed(cmp.Compare[int])
+	live := map[*Handle[int]]bool{}
+	var handles []*Handle[int]
+	for i := 0; i < 3000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			if len(handles) > 0 {
+				h := handles[r.Intn(len(handles))]
+				assert.Equal(t, live[h], pq.Remove(h))
+				delete(live, h)
+			}
+		case 1:
+			if len(handles) > 0 {
+				h := handles[r.Intn(len(handles))]
+				assert.Equal(t, live[h], pq.Update(h, r.Intn(1000)))
+			}
+		default:
+			h := pq.Push(r.Intn(1000))
+			handles = append(handles, h)
+			live[h] = true
+		}
+	}
+	isIndexedHeap(t, pq)
+
+	var want []int
+	for h := range live {
+		want = append(want, h.Value())
+	}
+	slices.Sort(want)
+	var got []int
+	for pq.Len() > 0 {
+		v, _ := pq.Pop()
+		got = append(got, v)
+	}
+	assert.Equal(t, want, got)
+}
+
+type intKey int
+
+func (k intKey) CompareTo(o rb_tree.Key) int {
+	return int(k) - int(o.(intKey))
+}
+
+func TestByCompareTo(t *testing.T) {
+	pq := New(ByCompareTo[rb_tree.Key])
+	pq.Push(intKey(3))
+	pq.Push(intKey(1))
+	pq.Push(intKey(2))
+	assert.Equal(t, []rb_tree.Key{intKey(1), intKey(2), intKey(3)}, drain(pq))
+}
@@ -0,0 +1,304 @@
+package heap
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ Heap[int] = (*PriorityQueue[int])(nil)
+	_ Heap[int] = (*PairingHeap[int])(nil)
+	_ Heap[int] = (*FibonacciHeap[int])(nil)
+)
+
+var heapKinds = map[string]func() Heap[int]{
+	"binary":    func() Heap[int] { return New(cmp.Compare[int]) },
+	"pairing":   func() Heap[int] { return NewPairing(cmp.Compare[int]) },
+	"fibonacci": func() Heap[int] { return NewFibonacci(cmp.Compare[int]) },
+}
+
+func drainHeap(h Heap[int]) []int {
+	var res []int
+	for {
+		v, ok := h.Pop()
+		if !ok {
+			return res
+		}
+		res = append(res, v)
+	}
+}
+
+func TestHeap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for name, newHeap := range heapKinds {
+		h := newHeap()
+		_, ok := h.Pop()
+		assert.False(t, ok, name)
+		_, ok = h.Peek()
+		assert.False(t, ok, name)
+
+		var want []int
+		for i := 0; i < 1000; i++ {
+			// interleave pops with pushes, so that pops happen on trees of all shapes
+			if i%3 == 2 {
+				v, ok := h.Pop()
+				assert.True(t, ok)
+				slices.Sort(want)
+				assert.Equal(t, want[0], v, name)
+				want = want[1:]
+				continue
+			}
+			v := r.Intn(100)
+			h.Push(v)
+			want = append(want, v)
+		}
+		assert.Equal(t, len(want), h.Len(), name)
+		v, _ := h.Peek()
+		slices.Sort(want)
+		assert.Equal(t, want[0], v, name)
+		assert.Equal(t, want, drainHeap(h), name)
+	}
+}
+
+// decreaser is what the tests need from PairingHeap and FibonacciHeap
+type decreaser[N any] interface {
+	Heap[int]
+	Insert(v int) N
+	DecreaseKey(n N, v int) bool
+	Contains(n N) bool
+}
+
+func testDecreaseKey[N interface{ Value() int }](t *testing.T, h decreaser[N]) {
+	r := rand.New(rand.NewSource(2))
+	var nodes []N
+	for i := 0; i < 2000; i++ {
+		switch r.Intn(5) {
+		case 0:
+			if v, ok := h.Pop(); ok {
+				// every node left must come after the popped value
+				for _, n := range nodes {
+					if h.Contains(n) {
+						assert.LessOrEqual(t, v, n.Value())
+					}
+				}
+			}
+		case 1, 2:
+			if len(nodes) > 0 {
+				n := nodes[r.Intn(len(nodes))]
+				v := n.Value() - r.Intn(50)
+				assert.Equal(t, h.Contains(n), h.DecreaseKey(n, v))
+			}
+		default:
+			nodes = append(nodes, h.Insert(r.Intn(10000)))
+		}
+	}
+
+	var want []int
+	for _, n := range nodes {
+		if h.Contains(n) {
+			want = append(want, n.Value())
+			assert.False(t, h.DecreaseKey(n, n.Value()+1), "an increase is rejected")
+		}
+	}
+	slices.Sort(want)
+	assert.Equal(t, want, drainHeap(h))
+}
+
+func TestPairingHeap_DecreaseKey(t *testing.T) {
+	testDecreaseKey[*PairingNode[int]](t, NewPairing(cmp.Compare[int]))
+}
+
+func TestFibonacciHeap_DecreaseKey(t *testing.T) {
+	testDecreaseKey[*FibNode[int]](t, NewFibonacci(cmp.Compare[int]))
+}
+
+func TestPairingHeap_Meld(t *testing.T) {
+	a, b, c := NewPairing(cmp.Compare[int]), NewPairing(cmp.Compare[int]), NewPairing(cmp.Compare[int])
+	na := a.Insert(5)
+	nb := b.Insert(3)
+	b.Insert(8)
+	nc := c.Insert(7)
+
+	b.Meld(c)
+	a.Meld(b)
+	a.Meld(a)
+	a.Meld(NewPairing(cmp.Compare[int]))
+	assert.Equal(t, 4, a.Len())
+	assert.Zero(t, b.Len())
+	assert.True(t, a.Contains(na))
+	assert.True(t, a.Contains(nb))
+	assert.True(t, a.Contains(nc), "nodes follow two melds")
+	assert.False(t, b.Contains(nb))
+	assert.False(t, b.DecreaseKey(nc, 0))
+
+	// the emptied heaps are still usable and own their new nodes only
+	nb2 := b.Insert(1)
+	assert.True(t, b.Contains(nb2))
+	assert.False(t, a.Contains(nb2))
+
+	assert.True(t, a.DecreaseKey(nc, 1))
+	assert.Equal(t, []int{1, 3, 5, 8}, drainHeap(a))
+	assert.False(t, a.Contains(na))
+}
+
+func TestFibonacciHeap_Meld(t *testing.T) {
+	a, b, c := NewFibonacci(cmp.Compare[int]), NewFibonacci(cmp.Compare[int]), NewFibonacci(cmp.Compare[int])
+	na := a.Insert(5)
+	nb := b.Insert(3)
+	b.Insert(8)
+	nc := c.Insert(7)
+
+	b.Meld(c)
+	a.Meld(b)
+	a.Meld(a)
+	assert.Equal(t, 4, a.Len())
+	assert.Zero(t, b.Len())
+	assert.True(t, a.Contains(nc))
+	assert.False(t, b.Contains(nb))
+
+	nb2 := b.Insert(1)
+	assert.False(t, a.Contains(nb2))
+	assert.True(t, a.DecreaseKey(nc, 1))
+	assert.Equal(t, []int{1, 3, 5, 8}, drainHeap(a))
+	assert.False(t, a.Contains(na))
+
+	// an empty heap takes the other one over
+	b.Meld(a)
+	assert.Equal(t, 1, b.Len())
+}
+
+// BenchmarkHeap_PushPop keeps a heap at a steady size, the pairing heap is the fastest here as a pushed value
+// which comes late stays a child of the root, the Fibonacci heap pays for consolidating its roots
+func BenchmarkHeap_PushPop(b *testing.B) {
+	for _, name := range []string{"binary", "pairing", "fibonacci"} {
+		b.Run(name, func(b *testing.B) {
+			h := heapKinds[name]()
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < benchSize; i++ {
+				h.Push(r.Int())
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				v, _ := h.Pop()
+				h.Push(v + r.Intn(benchSize))
+			}
+		})
+	}
+}
+
+// BenchmarkHeap_Meld merges the queues of 64 workers and pops once, the binary heap copies and rebuilds on every meld,
+// the Fibonacci heap melds in O(1) but its first pop links all the roots, the pairing heap wins by far
+func BenchmarkHeap_Meld(b *testing.B) {
+	const workers, perWorker = 64, 1024
+	build := func(push func(int)) {
+		for i := 0; i < perWorker; i++ {
+			push(i)
+		}
+	}
+	b.Run("binary", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			heaps := make([]*PriorityQueue[int], workers)
+			for w := range heaps {
+				heaps[w] = New(cmp.Compare[int])
+				build(heaps[w].Push)
+			}
+			b.StartTimer()
+			for w := 1; w < workers; w++ {
+				heaps[0].Meld(heaps[w])
+			}
+			heaps[0].Pop()
+		}
+	})
+	b.Run("pairing", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			heaps := make([]*PairingHeap[int], workers)
+			for w := range heaps {
+				heaps[w] = NewPairing(cmp.Compare[int])
+				build(heaps[w].Push)
+			}
+			b.StartTimer()
+			for w := 1; w < workers; w++ {
+				heaps[0].Meld(heaps[w])
+			}
+			heaps[0].Pop()
+		}
+	})
+	b.Run("fibonacci", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			heaps := make([]*FibonacciHeap[int], workers)
+			for w := range heaps {
+				heaps[w] = NewFibonacci(cmp.Compare[int])
+				build(heaps[w].Push)
+			}
+			b.StartTimer()
+			for w := 1; w < workers; w++ {
+				heaps[0].Meld(heaps[w])
+			}
+			heaps[0].Pop()
+		}
+	})
+}
+
+// BenchmarkHeap_DecreaseKey does 16 decrease-keys per pop, as a shortest path search on a dense graph does
+// the indexed binary heap and the pairing heap are close, the amortized O(1) of the Fibonacci heap does not pay off
+func BenchmarkHeap_DecreaseKey(b *testing.B) {
+	const size, perPop = benchSize, 16
+	b.Run("indexed", func(b *testing.B) {
+		r := rand.New(rand.NewSource(1))
+		h := NewIndexed(cmp.Compare[int])
+		nodes := make([]*Handle[int], size)
+		for i := range nodes {
+			nodes[i] = h.Push(r.Int())
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < perPop; j++ {
+				n := nodes[r.Intn(size)]
+				h.Update(n, n.Value()/2)
+			}
+			h.Pop()
+			nodes[r.Intn(size)] = h.Push(r.Int())
+		}
+	})
+	b.Run("pairing", func(b *testing.B) {
+		r := rand.New(rand.NewSource(1))
+		h := NewPairing(cmp.Compare[int])
+		nodes := make([]*PairingNode[int], size)
+		for i := range nodes {
+			nodes[i] = h.Insert(r.Int())
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < perPop; j++ {
+				n := nodes[r.Intn(size)]
+				h.DecreaseKey(n, n.Value()/2)
+			}
+			h.Pop()
+			nodes[r.Intn(size)] = h.Insert(r.Int())
+		}
+	})
+	b.Run("fibonacci", func(b *testing.B) {
+		r := rand.New(rand.NewSource(1))
+		h := NewFibonacci(cmp.Compare[int])
+		nodes := make([]*FibNode[int], size)
+		for i := range nodes {
+			nodes[i] = h.Insert(r.Int())
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < perPop; j++ {
+				n := nodes[r.Intn(size)]
+				h.DecreaseKey(n, n.Value()/2)
+			}
+			h.Pop()
+			nodes[r.Intn(size)] = h.Insert(r.Int())
+		}
+	})
+}
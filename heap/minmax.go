@@ -0,0 +1,222 @@
+package heap
+
+import "math/bits"
+
+// MinMaxHeap is a double-ended priority queue stored in a slice
+// the levels of the tree alternate between min levels, whose values come before all their descendants,
+// and max levels, whose values come after them, so the first value is the root and the last one is a child of it
+// Min and Max take O(1), Push, PopMin and PopMax take O(logN)
+// the zero value is not usable, a MinMaxHeap must be created with NewMinMax
+type MinMaxHeap[T any] struct {
+	items []T
+	cmp   func(a, b T) int
+}
+
+// NewMinMax returns an empty min-max heap ordered by cmp
+func NewMinMax[T any](cmp func(a, b T) int) *MinMaxHeap[T] {
+	return &MinMaxHeap[T]{cmp: cmp}
+}
+
+func (h *MinMaxHeap[T]) Len() int {
+	return len(h.items)
+}
+
+// isMinLevel returns whether position i is on a min level, the root is on level 0
+func isMinLevel(i int) bool {
+	return bits.Len(uint(i+1))%2 == 1
+}
+
+// before returns whether the value at i must stay before the value at j in the order of its level,
+// i.e. it comes first on a min level and last on a max level
+func (h *MinMaxHeap[T]) before(i, j int, min bool) bool {
+	c := h.cmp(h.items[i], h.items[j])
+	if min {
+		return c < 0
+	}
+	return c > 0
+}
+
+func (h *MinMaxHeap[T]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// Min returns the first value, ok is false when the heap is empty
+func (h *MinMaxHeap[T]) Min() (v T, ok bool) {
+	if len(h.items) == 0 {
+		return v, false
+	}
+	return h.items[0], true
+}
+
+// maxIndex returns the position of the last value, -1 when the heap is empty
+func (h *MinMaxHeap[T]) maxIndex() int {
+	switch len(h.items) {
+	case 0:
+		return -1
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.cmp(h.items[1], h.items[2]) >= 0 {
+		return 1
+	}
+	return 2
+}
+
+// Max returns the last value, ok is false when the heap is empty
+func (h *MinMaxHeap[T]) Max() (v T, ok bool) {
+	i := h.maxIndex()
+	if i < 0 {
+		return v, false
+	}
+	return h.items[i], true
+}
+
+// Push adds v in O(logN)
+func (h *MinMaxHeap[T]) Push(v T) {
+	h.items = append(h.items, v)
+	i := len(h.items) - 1
+	if i == 0 {
+		return
+	}
+	// v first goes to the kind of level it belongs to by comparing with its parent, then climbs those levels only
+	parent := (i - 1) / 2
+	min := isMinLevel(i)
+	if h.before(i, parent, !min) {
+		h.swap(i, parent)
+		i, min = parent, !min
+	}
+	for i > 2 {
+		grandparent := ((i-1)/2 - 1) / 2
+		if !h.before(i, grandparent, min) {
+			break
+		}
+		h.swap(i, grandparent)
+		i = grandparent
+	}
+}
+
+// PopMin removes and returns the first value in O(logN), ok is false when the heap is empty
+func (h *MinMaxHeap[T]) PopMin() (v T, ok bool) {
+	if len(h.items) == 0 {
+		return v, false
+	}
+	return h.removeAt(0), true
+}
+
+// PopMax removes and returns the last value in O(logN), ok is false when the heap is empty
+func (h *MinMaxHeap[T]) PopMax() (v T, ok bool) {
+	i := h.maxIndex()
+	if i < 0 {
+		return v, false
+	}
+	return h.removeAt(i), true
+}
+
+// removeAt replaces the value at i, which is the root of its subtree, by the last value and trickles it down
+func (h *MinMaxHeap[T]) removeAt(i int) T {
+	n := len(h.items) - 1
+	v := h.items[i]
+	h.items[i] = h.items[n]
+	var zero T
+	h.items[n] = zero
+	h.items = h.items[:n]
+	if i < n {
+		h.down(i)
+	}
+	return v
+}
+
+// down moves the value at i down the levels of its kind, swapping with the child or grandchild which fits best
+func (h *MinMaxHeap[T]) down(i int) {
+	min := isMinLevel(i)
+	n := len(h.items)
+	for {
+		// m is the descendant within two levels which comes first on a min level, or last on a max level
+		first := 2*i + 1
+		if first >= n {
+			return
+		}
+		m := first
+		for _, c := range [...]int{first + 1, 2*first + 1, 2*first + 2, 2*first + 3, 2*first + 4} {
+			if c < n && h.before(c, m, min) {
+				m = c
+			}
+		}
+		if !h.before(m, i, min) {
+			return
+		}
+		h.swap(m, i)
+		if m <= first+1 {
+			// a child is on the other kind of level, the value cannot go further
+			return
+		}
+		// m is a grandchild, the value now there may be out of order with its parent
+		if parent := (m - 1) / 2; h.before(parent, m, min) {
+			h.swap(m, parent)
+		}
+		i = m
+	}
+}
+
+// Clear removes all values
+func (h *MinMaxHeap[T]) Clear() {
+	clear(h.items)
+	h.items = h.items[:0]
+}
+
+// TopK keeps the k values which come last among those it is offered, in O(logK) per offer
+// it is backed by a MinMaxHeap, the smallest kept value is evicted when a larger one arrives
+type TopK[T any] struct {
+	heap MinMaxHeap[T]
+	k    int
+}
+
+// NewTopK returns an empty TopK keeping k values ordered by cmp, k must be positive
+func NewTopK[T any](k int, cmp func(a, b T) int) *TopK[T] {
+	if k <= 0 {
+		panic("heap: k of a TopK must be positive")
+	}
+	return &TopK[T]{heap: MinMaxHeap[T]{cmp: cmp}, k: k}
+}
+
+// Offer considers v, it returns the value which is no longer in the top k, which may be v itself,
+// and false when nothing was dropped because fewer than k values were kept
+func (t *TopK[T]) Offer(v T) (dropped T, ok bool) {
+	if t.heap.Len() < t.k {
+		t.heap.Push(v)
+		return dropped, false
+	}
+	if min, _ := t.heap.Min(); t.heap.cmp(v, min) <= 0 {
+		return v, true
+	}
+	dropped, _ = t.heap.PopMin()
+	t.heap.Push(v)
+	return dropped, true
+}
+
+func (t *TopK[T]) Len() int {
+	return t.heap.Len()
+}
+
+// Min returns the smallest kept value, the bar an offered value has to beat once the top is full
+func (t *TopK[T]) Min() (T, bool) {
+	return t.heap.Min()
+}
+
+// Max returns the largest kept value
+func (t *TopK[T]) Max() (T, bool) {
+	return t.heap.Max()
+}
+
+// Values returns the kept values from the largest to the smallest, the TopK is left unchanged
+func (t *TopK[T]) Values() []T {
+	c := MinMaxHeap[T]{items: append([]T(nil), t.heap.items...), cmp: t.heap.cmp}
+	res := make([]T, 0, c.Len())
+	for c.Len() > 0 {
+		v, _ := c.PopMax()
+		res = append(res, v)
+	}
+	return res
+}
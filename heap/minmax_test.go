@@ -0,0 +1,153 @@
+package heap
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// isMinMaxHeap checks every value against its ancestors, according to their levels
+func isMinMaxHeap[T any](h *MinMaxHeap[T]) bool {
+	for i := 1; i < len(h.items); i++ {
+		for a := (i - 1) / 2; ; a = (a - 1) / 2 {
+			c := h.cmp(h.items[i], h.items[a])
+			if isMinLevel(a) && c < 0 || !isMinLevel(a) && c > 0 {
+				return false
+			}
+			if a == 0 {
+				break
+			}
+		}
+	}
+	return true
+}
+
+func TestMinMaxHeap(t *testing.T) {
+	h := NewMinMax(cmp.Compare[int])
+	_, ok := h.Min()
+	assert.False(t, ok)
+	_, ok = h.PopMax()
+	assert.False(t, ok)
+
+	h.Push(5)
+	v, _ := h.Max()
+	assert.Equal(t, 5, v)
+	h.Push(1)
+	v, _ = h.Max()
+	assert.Equal(t, 5, v)
+	for _, v := range []int{9, 3, 7, 2, 8} {
+		h.Push(v)
+	}
+	assert.True(t, isMinMaxHeap(h))
+	v, _ = h.Min()
+	assert.Equal(t, 1, v)
+	v, _ = h.Max()
+	assert.Equal(t, 9, v)
+
+	v, _ = h.PopMax()
+	assert.Equal(t, 9, v)
+	v, _ = h.PopMin()
+	assert.Equal(t, 1, v)
+	v, _ = h.PopMax()
+	assert.Equal(t, 8, v)
+	assert.Equal(t, 4, h.Len())
+	assert.True(t, isMinMaxHeap(h))
+
+	h.Clear()
+	assert.Zero(t, h.Len())
+}
+
+func TestMinMaxHeap_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewMinMax(cmp.Compare[int])
+	var want []int
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(4) {
+		case 0:
+			v, ok := h.PopMin()
+			assert.Equal(t, len(want) > 0, ok)
+			if ok {
+				assert.Equal(t, want[0], v)
+				want = want[1:]
+			}
+		case 1:
+			v, ok := h.PopMax()
+			assert.Equal(t, len(want) > 0, ok)
+			if ok {
+				assert.Equal(t, want[len(want)-1], v)
+				want = want[:len(want)-1]
+			}
+		default:
+			v := r.Intn(1000)
+			h.Push(v)
+			i, _ := slices.BinarySearch(want, v)
+			want = slices.Insert(want, i, v)
+		}
+		if len(want) > 0 {
+			min, _ := h.Min()
+			max, _ := h.Max()
+			assert.Equal(t, want[0], min)
+			assert.Equal(t, want[len(want)-1], max)
+		}
+	}
+	assert.True(t, isMinMaxHeap(h))
+	assert.Equal(t, len(want), h.Len())
+}
+
+func TestTopK(t *testing.T) {
+	assert.Panics(t, func() { NewTopK(0, cmp.Compare[int]) })
+
+	top := NewTopK(3, cmp.Compare[int])
+	_, ok := top.Offer(5)
+	assert.False(t, ok)
+	top.Offer(1)
+	top.Offer(9)
+	dropped, ok := top.Offer(0)
+	assert.True(t, ok)
+	assert.Equal(t, 0, dropped, "a value below the bar is dropped at once")
+	dropped, _ = top.Offer(7)
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, []int{9, 7, 5}, top.Values())
+	assert.Equal(t, 3, top.Len())
+	v, _ := top.Min()
+	assert.Equal(t, 5, v)
+	v, _ = top.Max()
+	assert.Equal(t, 9, v)
+
+	r := rand.New(rand.NewSource(2))
+	values := r.Perm(1000)
+	top = NewTopK(10, cmp.Compare[int])
+	for _, v := range values {
+		top.Offer(v)
+	}
+	assert.Equal(t, []int{999, 998, 997, 996, 995, 994, 993, 992, 991, 990}, top.Values())
+}
+
+// the top 100 of a stream, kept in a TopK or in an RBTree as done before
+func BenchmarkTopK(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	top := NewTopK(100, cmp.Compare[int])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		top.Offer(r.Intn(1 << 30))
+	}
+}
+
+func BenchmarkTopK_RBTree(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	var tree rb_tree.RBTree
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := intKey(r.Intn(1 << 30))
+		if tree.Size() < 100 {
+			tree.Put(k, nil)
+		} else if k.CompareTo(tree.Min()) > 0 {
+			tree.DeleteMin()
+			tree.Put(k, nil)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package heap
+
+import "github.com/derekcdz/dsgym/internal/ownership"
+
+// PairingNode designates a value inserted into a PairingHeap, it is the way to decrease the value later
+type PairingNode[T any] struct {
+	value   T
+	child   *PairingNode[T] // leftmost child
+	sibling *PairingNode[T] // next sibling to the right
+	prev    *PairingNode[T] // left sibling, or the parent for the leftmost child
+
+	belongsTo *ownership.Token[PairingHeap[T]] // merged by Meld
+}
+
+func (n *PairingNode[T]) Value() T {
+	return n.value
+}
+
+// PairingHeap is a heap-ordered multiway tree with O(1) Push, Meld and amortized o(logN) DecreaseKey,
+// Pop takes amortized O(logN) by merging the children of the root in pairs, then from right to left
+// it is simple and usually the fastest of the mergeable heaps in practice
+// the zero value is not usable, a PairingHeap must be created with NewPairing
+type PairingHeap[T any] struct {
+	root  *PairingNode[T]
+	len   int
+	cmp   func(a, b T) int
+	owner *ownership.Token[PairingHeap[T]]
+}
+
+// NewPairing returns an empty pairing heap ordered by cmp
+func NewPairing[T any](cmp func(a, b T) int) *PairingHeap[T] {
+	h := &PairingHeap[T]{cmp: cmp}
+	h.owner = ownership.New(h)
+	return h
+}
+
+func (h *PairingHeap[T]) Len() int {
+	return h.len
+}
+
+// Contains returns whether n designates a value in the heap
+func (h *PairingHeap[T]) Contains(n *PairingNode[T]) bool {
+	return n != nil && n.belongsTo.Owner() == h
+}
+
+// owns is Contains for the methods which modify h, it also shortens the way from n to the owner of h
+func (h *PairingHeap[T]) owns(n *PairingNode[T]) bool {
+	if !h.Contains(n) {
+		return false
+	}
+	n.belongsTo.Compress()
+	n.belongsTo = h.owner
+	return true
+}
+
+// meld links the detached trees a and b, the root which comes later becomes the leftmost child of the other one
+func (h *PairingHeap[T]) meld(a, b *PairingNode[T]) *PairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.cmp(b.value, a.value) < 0 {
+		a, b = b, a
+	}
+	b.prev = a
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	return a
+}
+
+// Push adds v in O(1)
+func (h *PairingHeap[T]) Push(v T) {
+	h.Insert(v)
+}
+
+// Insert adds v in O(1) and returns its node, for DecreaseKey
+func (h *PairingHeap[T]) Insert(v T) *PairingNode[T] {
+	n := &PairingNode[T]{value: v, belongsTo: h.owner}
+	h.root = h.meld(h.root, n)
+	h.len++
+	return n
+}
+
+// Peek returns the first value without removing it, ok is false when the heap is empty
+func (h *PairingHeap[T]) Peek() (v T, ok bool) {
+	if h.root == nil {
+		return v, false
+	}
+	return h.root.value, true
+}
+
+// Pop removes and returns the first value, ok is false when the heap is empty
+func (h *PairingHeap[T]) Pop() (v T, ok bool) {
+	r := h.root
+	if r == nil {
+		return v, false
+	}
+
+	// first pass: meld the children in pairs from left to right, stacking the results through sibling
+	var stack *PairingNode[T]
+	for c := r.child; c != nil; {
+		a, b := c, c.sibling
+		c = nil
+		if b != nil {
+			c = b.sibling
+			b.prev, b.sibling = nil, nil
+		}
+		a.prev, a.sibling = nil, nil
+		m := h.meld(a, b)
+		m.sibling = stack
+		stack = m
+	}
+	// second pass: meld the pairs from right to left into one tree
+	var root *PairingNode[T]
+	for stack != nil {
+		next := stack.sibling
+		stack.sibling = nil
+		root = h.meld(root, stack)
+		stack = next
+	}
+
+	h.root = root
+	h.len--
+	r.child = nil
+	r.belongsTo = nil
+	return r.value, true
+}
+
+// DecreaseKey replaces the value of n with v, which must not come after it, in amortized o(logN)
+// it returns false and does nothing when n does not belong to the heap or v comes after the current value
+func (h *PairingHeap[T]) DecreaseKey(n *PairingNode[T], v T) bool {
+	if !h.owns(n) || h.cmp(v, n.value) > 0 {
+		return false
+	}
+	n.value = v
+	if n == h.root {
+		return true
+	}
+	// cut the subtree of n and meld it with the root
+	if n.prev.child == n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling != nil {
+		n.sibling.prev = n.prev
+	}
+	n.prev, n.sibling = nil, nil
+	h.root = h.meld(h.root, n)
+	return true
+}
+
+// Meld moves all values of other into h in O(1) and leaves other empty
+// the nodes of other belong to h afterwards
+func (h *PairingHeap[T]) Meld(other *PairingHeap[T]) {
+	if other == h || other.root == nil {
+		return
+	}
+	h.root = h.meld(h.root, other.root)
+	h.len += other.len
+	other.root = nil
+	other.len = 0
+	h.owner = h.owner.Merge(other.owner)
+	other.owner = ownership.New(other)
+}
@@ -0,0 +1,65 @@
+// Package ownership tells which container a node belongs to, when a container can absorb another one in O(1)
+// every container has a token and its nodes point at it, absorbing a container merges its token with the absorbing one,
+// so the absorbed nodes change hands without being visited, like the sets of a union-find
+// merges are by rank, so a node is never more than log2(k) tokens away from its container after k merges,
+// which bounds the cost of Owner without writing to the tokens
+package ownership
+
+// Token stands for a container of type C in the nodes of the container
+type Token[C any] struct {
+	container *C // nil once the container let go of its nodes
+	forward   *Token[C]
+	rank      int // bounds the number of forwards from a token to this one
+}
+
+// New returns the token of c
+func New[C any](c *C) *Token[C] {
+	return &Token[C]{container: c}
+}
+
+// Owner returns the container which t stands for after the forwards, nil when t is nil or the container let go of its nodes
+// it only reads the tokens, so the nodes of a container may be checked by several goroutines at once
+func (t *Token[C]) Owner() *C {
+	if t == nil {
+		return nil
+	}
+	for t.forward != nil {
+		t = t.forward
+	}
+	return t.container
+}
+
+// Compress points the tokens on the way straight at the last one, so later lookups are short
+// it writes to the tokens, so only a method which may modify the container should call it
+func (t *Token[C]) Compress() {
+	root := t
+	for root.forward != nil {
+		root = root.forward
+	}
+	for t != root {
+		next := t.forward
+		t.forward = root
+		t = next
+	}
+}
+
+// Merge hands the nodes of from over to the container of t, both must be the tokens of containers
+// the token which stands for the container of t afterwards is returned, it is the one of higher rank,
+// so the container must keep it in place of t
+func (t *Token[C]) Merge(from *Token[C]) *Token[C] {
+	if t.rank < from.rank {
+		t, from = from, t
+		t.container, from.container = from.container, t.container
+	}
+	from.container = nil
+	from.forward = t
+	if t.rank == from.rank {
+		t.rank++
+	}
+	return t
+}
+
+// Release lets go of the nodes of t, e.g. when its container is cleared, they belong to no container afterwards
+func (t *Token[C]) Release() {
+	t.container = nil
+}
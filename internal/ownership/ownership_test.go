@@ -0,0 +1,61 @@
+package ownership
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type box struct{}
+
+func TestToken(t *testing.T) {
+	a, b, c := &box{}, &box{}, &box{}
+	ta, tb, tc := New(a), New(b), New(c)
+	assert.Same(t, a, ta.Owner())
+	assert.Nil(t, (*Token[box])(nil).Owner())
+
+	tb = tb.Merge(ta)
+	assert.Same(t, b, ta.Owner())
+	assert.Same(t, b, tb.Owner())
+	assert.Equal(t, 1, tb.rank)
+
+	// the token of c has the lower rank, so it is hung under the one of b, which now stands for c
+	root := tc.Merge(tb)
+	assert.Same(t, tb, root)
+	assert.Same(t, c, ta.Owner())
+	assert.Same(t, c, tc.Owner())
+	assert.Nil(t, tc.container)
+
+	root.Release()
+	assert.Nil(t, ta.Owner())
+	assert.Nil(t, tc.Owner())
+}
+
+func TestToken_Compress(t *testing.T) {
+	a, b, c, d := &box{}, &box{}, &box{}, &box{}
+	ta, tb, tc, td := New(a), New(b), New(c), New(d)
+	tb = tb.Merge(ta)
+	td = td.Merge(tc)
+	tb = tb.Merge(td) // two trees of rank 1, so the path from tc is two forwards long
+	assert.Same(t, td, tc.forward)
+	assert.Same(t, b, tc.Owner())
+
+	tc.Compress()
+	assert.Same(t, tb, tc.forward)
+	assert.Same(t, b, tc.Owner())
+}
+
+// a chain of merges, each absorbing the container of the previous one, stays shallow
+func TestToken_MergeChain(t *testing.T) {
+	first := New(&box{})
+	prev := first
+	for i := 0; i < 1000; i++ {
+		prev = New(&box{}).Merge(prev)
+	}
+	depth := 0
+	for x := first; x.forward != nil; x = x.forward {
+		depth++
+	}
+	assert.LessOrEqual(t, depth, 10)
+	assert.Same(t, prev.container, first.Owner())
+}
@@ -0,0 +1,263 @@
+// A generic doubly linked list, the typed counterpart of package list
+// it keeps the same ownership checks: an element can only be used with the list it belongs to
+package glist
+
+import "github.com/derekcdz/dsgym/internal/ownership"
+
+type Element[T any] struct {
+	prev *Element[T]
+	next *Element[T]
+
+	belongsTo *ownership.Token[List[T]] // merged by MoveBackList and MoveFrontList
+
+	Value T
+}
+
+// list returns the list e belongs to, nil when e was removed or its list was re-initialized
+func (e *Element[T]) list() *List[T] {
+	return e.belongsTo.Owner()
+}
+
+// owns returns whether e belongs to l, and shortens the way from e to the owner of l
+func (l *List[T]) owns(e *Element[T]) bool {
+	if e.list() != l {
+		return false
+	}
+	e.belongsTo.Compress()
+	e.belongsTo = l.owner
+	return true
+}
+
+func (e *Element[T]) Next() *Element[T] {
+	if e.list() == nil || e.next.belongsTo == nil {
+		return nil
+	}
+	return e.next
+}
+
+func (e *Element[T]) Prev() *Element[T] {
+	if e.list() == nil || e.prev.belongsTo == nil {
+		return nil
+	}
+	return e.prev
+}
+
+type IElement[T any] interface {
+	Next() *Element[T]
+	Prev() *Element[T]
+}
+
+type List[T any] struct {
+	root  Element[T]
+	len   int
+	owner *ownership.Token[List[T]]
+}
+
+func New[T any]() *List[T] {
+	l := List[T]{}
+	l.Init()
+	return &l
+}
+
+type IList[T any] interface {
+	Back() *Element[T]
+	Front() *Element[T]
+	Init() *List[T]
+	InsertAfter(v T, mark *Element[T]) *Element[T]
+	InsertBefore(v T, mark *Element[T]) *Element[T]
+	Len() int
+	MoveAfter(e, mark *Element[T])
+	MoveBackList(other *List[T])
+	MoveBefore(e, mark *Element[T])
+	MoveFrontList(other *List[T])
+	MoveToBack(e *Element[T])
+	MoveToFront(e *Element[T])
+	PushBack(v T) *Element[T]
+	PushBackList(other *List[T])
+	PushFront(v T) *Element[T]
+	PushFrontList(other *List[T])
+	Remove(e *Element[T]) T
+}
+
+func (l *List[T]) Back() *Element[T] {
+	back := l.root.prev
+	if back == &l.root {
+		return nil
+	}
+	return back
+}
+
+func (l *List[T]) Front() *Element[T] {
+	front := l.root.next
+	if front == &l.root {
+		return nil
+	}
+	return front
+}
+
+// Init clears the list, the elements it held no longer belong to it
+func (l *List[T]) Init() *List[T] {
+	l.root.prev = &l.root
+	l.root.next = &l.root
+	l.len = 0
+	if l.owner != nil {
+		l.owner.Release()
+	}
+	l.owner = ownership.New(l)
+	return l
+}
+
+func (l *List[T]) checkAndInit() {
+	if l.root.next == nil {
+		l.Init()
+	}
+}
+
+// insert links e after at, at must be l.root or an element of l
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.belongsTo = l.owner
+	l.len++
+	return e
+}
+
+func (l *List[T]) insertValue(v T, at *Element[T]) *Element[T] {
+	return l.insert(&Element[T]{Value: v}, at)
+}
+
+// move relinks e after at, both belong to l
+func (l *List[T]) move(e, at *Element[T]) {
+	if e == at {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if !l.owns(mark) {
+		return nil
+	}
+	return l.insertValue(v, mark)
+}
+
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	if !l.owns(mark) {
+		return nil
+	}
+	return l.insertValue(v, mark.prev)
+}
+
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+func (l *List[T]) MoveAfter(e, mark *Element[T]) {
+	if e == mark || !l.owns(e) || !l.owns(mark) {
+		return
+	}
+	l.move(e, mark)
+}
+
+func (l *List[T]) MoveBefore(e, mark *Element[T]) {
+	if e == mark || !l.owns(e) || !l.owns(mark) {
+		return
+	}
+	l.move(e, mark.prev)
+}
+
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	l.checkAndInit()
+	if e == l.root.prev || !l.owns(e) {
+		return
+	}
+	l.move(e, l.root.prev)
+}
+
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	l.checkAndInit()
+	if e == l.root.next || !l.owns(e) {
+		return
+	}
+	l.move(e, &l.root)
+}
+
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.checkAndInit()
+	return l.insertValue(v, l.root.prev)
+}
+
+// PushBackList inserts a copy of other at the back of l, other may be l itself
+func (l *List[T]) PushBackList(other *List[T]) {
+	l.checkAndInit()
+	// the length is taken first, so that appending l to itself stops after the original elements
+	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
+		l.insertValue(e.Value, l.root.prev)
+	}
+}
+
+// MoveBackList moves all elements of other to the back of l in O(1), other is left empty
+// the elements keep their identity and belong to l afterwards
+func (l *List[T]) MoveBackList(other *List[T]) {
+	l.checkAndInit()
+	l.splice(other, l.root.prev)
+}
+
+// MoveFrontList moves all elements of other to the front of l in O(1), other is left empty
+func (l *List[T]) MoveFrontList(other *List[T]) {
+	l.checkAndInit()
+	l.splice(other, &l.root)
+}
+
+// splice links the elements of other after at and hands them over to l
+func (l *List[T]) splice(other *List[T], at *Element[T]) {
+	if other == l || other.Len() == 0 {
+		return
+	}
+	first, last := other.root.next, other.root.prev
+	first.prev = at
+	last.next = at.next
+	at.next.prev = last
+	at.next = first
+	l.len += other.len
+
+	l.owner = l.owner.Merge(other.owner)
+	other.owner = nil
+	other.Init()
+}
+
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.checkAndInit()
+	return l.insertValue(v, &l.root)
+}
+
+// PushFrontList inserts a copy of other at the front of l, other may be l itself
+func (l *List[T]) PushFrontList(other *List[T]) {
+	l.checkAndInit()
+	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
+		l.insertValue(e.Value, &l.root)
+	}
+}
+
+func (l *List[T]) Remove(e *Element[T]) T {
+	l.checkAndInit()
+	if !l.owns(e) {
+		var zero T
+		return zero
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+	e.belongsTo = nil
+	l.len--
+	return e.Value
+}
@@ -0,0 +1,231 @@
+package glist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ IList[int] = (*List[int])(nil)
+var _ IElement[int] = (*Element[int])(nil)
+
+// values returns the values of l front to back, and checks the links both ways
+func values[T any](t *testing.T, l *List[T]) []T {
+	res := []T{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		res = append(res, e.Value)
+	}
+	assert.Equal(t, l.Len(), len(res))
+
+	back := []T{}
+	for e := l.Back(); e != nil; e = e.Prev() {
+		back = append([]T{e.Value}, back...)
+	}
+	assert.Equal(t, res, back)
+	return res
+}
+
+func TestList_Push(t *testing.T) {
+	var l List[string]
+	assert.Nil(t, l.Front())
+	assert.Nil(t, l.Back())
+
+	b := l.PushBack("b")
+	l.PushFront("a")
+	l.PushBack("c")
+	assert.Equal(t, []string{"a", "b", "c"}, values(t, &l))
+	assert.Equal(t, "a", b.Prev().Value)
+	assert.Equal(t, "c", b.Next().Value)
+	assert.Nil(t, l.Front().Prev())
+	assert.Nil(t, l.Back().Next())
+}
+
+func TestList_Insert(t *testing.T) {
+	l := New[int]()
+	two := l.PushBack(2)
+	l.InsertBefore(1, two)
+	l.InsertAfter(3, two)
+	l.InsertAfter(4, l.Back())
+	assert.Equal(t, []int{1, 2, 3, 4}, values(t, l))
+
+	other := New[int]()
+	foreign := other.PushBack(9)
+	assert.Nil(t, l.InsertAfter(5, foreign))
+	assert.Nil(t, l.InsertBefore(5, foreign))
+	assert.Equal(t, 4, l.Len())
+}
+
+func TestList_Move(t *testing.T) {
+	l := New[int]()
+	e1 := l.PushBack(1)
+	e2 := l.PushBack(2)
+	e3 := l.PushBack(3)
+	e4 := l.PushBack(4)
+
+	l.MoveToFront(e3)
+	assert.Equal(t, []int{3, 1, 2, 4}, values(t, l))
+	l.MoveToBack(e1)
+	assert.Equal(t, []int{3, 2, 4, 1}, values(t, l))
+	l.MoveAfter(e3, e4)
+	assert.Equal(t, []int{2, 4, 3, 1}, values(t, l))
+	l.MoveBefore(e1, e2)
+	assert.Equal(t, []int{1, 2, 4, 3}, values(t, l))
+	l.MoveAfter(e1, e1)
+	l.MoveBefore(e3, e3)
+	assert.Equal(t, []int{1, 2, 4, 3}, values(t, l))
+
+	other := New[int]()
+	foreign := other.PushBack(9)
+	l.MoveToFront(foreign)
+	l.MoveAfter(foreign, e1)
+	l.MoveBefore(e1, foreign)
+	assert.Equal(t, []int{1, 2, 4, 3}, values(t, l))
+	assert.Equal(t, []int{9}, values(t, other))
+}
+
+func TestList_Remove(t *testing.T) {
+	l := New[int]()
+	e1 := l.PushBack(1)
+	e2 := l.PushBack(2)
+	l.PushBack(3)
+
+	assert.Equal(t, 2, l.Remove(e2))
+	assert.Equal(t, []int{1, 3}, values(t, l))
+	assert.Nil(t, e2.Next())
+	assert.Nil(t, e2.Prev())
+
+	// a removed element is no longer accepted
+	assert.Zero(t, l.Remove(e2))
+	l.MoveToFront(e2)
+	assert.Nil(t, l.InsertAfter(5, e2))
+	assert.Equal(t, []int{1, 3}, values(t, l))
+
+	other := New[int]()
+	assert.Zero(t, other.Remove(e1))
+	assert.Equal(t, []int{1, 3}, values(t, l))
+}
+
+// elements of a list which is re-initialized are disowned, even once the list holds new ones
+func TestList_RemoveAfterInit(t *testing.T) {
+	l := New[int]()
+	old := l.PushBack(1)
+	l.Init()
+	l.PushBack(2)
+
+	assert.Zero(t, l.Remove(old))
+	assert.Nil(t, old.Next())
+	assert.Equal(t, []int{2}, values(t, l))
+}
+
+func TestList_MoveBackList(t *testing.T) {
+	l := New[int]()
+	l.PushBack(1)
+	other := New[int]()
+	two := other.PushBack(2)
+	other.PushBack(3)
+
+	l.MoveBackList(other)
+	assert.Equal(t, []int{1, 2, 3}, values(t, l))
+	assert.Equal(t, []int{}, values(t, other))
+
+	// the moved elements keep their identity and belong to l
+	l.MoveToFront(two)
+	assert.Equal(t, []int{2, 1, 3}, values(t, l))
+	assert.Zero(t, other.Remove(two))
+	other.PushBack(4)
+	assert.Equal(t, []int{4}, values(t, other))
+
+	l.MoveBackList(l)
+	l.MoveBackList(New[int]())
+	var empty List[int]
+	empty.MoveBackList(l)
+	assert.Equal(t, []int{2, 1, 3}, values(t, &empty))
+	assert.Equal(t, 2, empty.Remove(two))
+}
+
+func TestList_MoveFrontList(t *testing.T) {
+	a, b, c := New[int](), New[int](), New[int]()
+	one := a.PushBack(1)
+	b.PushBack(2)
+	c.PushBack(3)
+
+	b.MoveFrontList(a)
+	c.MoveFrontList(b)
+	assert.Equal(t, []int{1, 2, 3}, values(t, c))
+	c.MoveToBack(one)
+	assert.Equal(t, []int{2, 3, 1}, values(t, c))
+
+	c.Init()
+	assert.Zero(t, c.Remove(one))
+	assert.Equal(t, []int{}, values(t, c))
+}
+
+func TestList_PushBackList(t *testing.T) {
+	l := New[int]()
+	l.PushBack(1)
+	l.PushBack(2)
+	other := New[int]()
+	other.PushBack(3)
+	other.PushBack(4)
+
+	l.PushBackList(other)
+	assert.Equal(t, []int{1, 2, 3, 4}, values(t, l))
+	assert.Equal(t, []int{3, 4}, values(t, other))
+
+	// the copies belong to l
+	l.Remove(l.Back())
+	assert.Equal(t, []int{3, 4}, values(t, other))
+
+	l.PushBackList(l)
+	assert.Equal(t, []int{1, 2, 3, 1, 2, 3}, values(t, l))
+
+	l.PushBackList(New[int]())
+	var empty List[int]
+	l.PushBackList(&empty)
+	assert.Equal(t, 6, l.Len())
+
+	empty.PushBackList(other)
+	assert.Equal(t, []int{3, 4}, values(t, &empty))
+}
+
+func TestList_PushFrontList(t *testing.T) {
+	l := New[int]()
+	l.PushBack(3)
+	l.PushBack(4)
+	other := New[int]()
+	other.PushBack(1)
+	other.PushBack(2)
+
+	l.PushFrontList(other)
+	assert.Equal(t, []int{1, 2, 3, 4}, values(t, l))
+	assert.Equal(t, []int{1, 2}, values(t, other))
+
+	l.PushFrontList(l)
+	assert.Equal(t, []int{1, 2, 3, 4, 1, 2, 3, 4}, values(t, l))
+
+	var empty List[int]
+	empty.PushFrontList(other)
+	assert.Equal(t, []int{1, 2}, values(t, &empty))
+}
+
+func TestList_All(t *testing.T) {
+	l := New[string]()
+	l.PushBack("a")
+	l.PushBack("b")
+	l.PushBack("c")
+
+	var res []string
+	for v := range l.All() {
+		res = append(res, v)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, res)
+
+	res = nil
+	for v := range l.Backward() {
+		res = append(res, v)
+		if v == "b" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"c", "b"}, res)
+}
@@ -0,0 +1,26 @@
+package list
+
+import "iter"
+
+// All returns an iterator over the values from front to back
+// the list must not be modified during the iteration
+func (l *List) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for e := l.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the values from back to front
+func (l *List) Backward() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for e := l.Back(); e != nil; e = e.Prev() {
+			if !yield(e.Value) {
+				return
+			}
+		}
+	}
+}
@@ -1,24 +1,44 @@
 // An imitation of container/list
 package list
 
+import "github.com/derekcdz/dsgym/internal/ownership"
+
 type Element struct {
 	prev *Element
 	next *Element
 
-	belongsTo *List
+	belongsTo *ownership.Token[List] // merged by MoveBackList and MoveFrontList
+	gen       uint64                 // bumped every time a Pool recycles the element
 
 	Value interface{}
 }
 
+// list returns the list e belongs to, nil when e was removed or its list was re-initialized
+// the root of a list belongs to no list, which is how Next and Prev detect the ends
+func (e *Element) list() *List {
+	return e.belongsTo.Owner()
+}
+
+// owns returns whether e belongs to l, and shortens the way from e to the owner of l
+// Next and Prev only resolve owners, the paths are shortened here because only one goroutine may modify l
+func (l *List) owns(e *Element) bool {
+	if e.list() != l {
+		return false
+	}
+	e.belongsTo.Compress()
+	e.belongsTo = l.owner
+	return true
+}
+
 func (e *Element) Next() *Element {
-	if e.belongsTo == nil || e.next.belongsTo == nil {
+	if e.list() == nil || e.next.belongsTo == nil {
 		return nil
 	}
 	return e.next
 }
 
 func (e *Element) Prev() *Element {
-	if e.belongsTo == nil || e.prev.belongsTo == nil {
+	if e.list() == nil || e.prev.belongsTo == nil {
 		return nil
 	}
 	return e.prev
@@ -30,8 +50,10 @@ type IElement interface {
 }
 
 type List struct {
-	root Element
-	len  int
+	root  Element
+	len   int
+	owner *ownership.Token[List]
+	pool  *Pool // set for the list of a PooledList
 }
 
 func New() *List {
@@ -42,20 +64,30 @@ func New() *List {
 
 type IList interface {
 	Back() *Element
+	Filter(pred func(v interface{}) bool) *List
+	Find(pred func(v interface{}) bool) *Element
 	Front() *Element
 	Init() *List
 	InsertAfter(v interface{}, mark *Element) *Element
 	InsertBefore(v interface{}, mark *Element) *Element
 	Len() int
+	Map(f func(v interface{}) interface{}) *List
 	MoveAfter(e, mark *Element)
+	MoveBackList(other *List)
 	MoveBefore(e, mark *Element)
+	MoveFrontList(other *List)
 	MoveToBack(e *Element)
 	MoveToFront(e *Element)
 	PushBack(v interface{}) *Element
 	PushBackList(other *List)
 	PushFront(v interface{}) *Element
 	PushFrontList(other *List)
+	Reduce(init interface{}, f func(acc, v interface{}) interface{}) interface{}
 	Remove(e *Element) interface{}
+	Reverse()
+	Rotate(n int)
+	Sort(less func(a, b interface{}) bool)
+	SplitAt(i int) *List
 }
 
 func (l *List) Back() *Element {
@@ -74,10 +106,15 @@ func (l *List) Front() *Element {
 	return front
 }
 
+// Init clears the list, the elements it held no longer belong to it
 func (l *List) Init() *List {
 	l.root.prev = &l.root
 	l.root.next = &l.root
 	l.len = 0
+	if l.owner != nil {
+		l.owner.Release()
+	}
+	l.owner = ownership.New(l)
 	return l
 }
 
@@ -88,36 +125,17 @@ func (l *List) checkAndInit() {
 }
 
 func (l *List) InsertAfter(v interface{}, mark *Element) *Element {
-	if mark.belongsTo != l {
+	if !l.owns(mark) {
 		return nil
 	}
-	e := &Element{
-		prev:      mark,
-		next:      mark.next,
-		belongsTo: l,
-		Value:     v,
-	}
-	e.prev.next = e
-	e.next.prev = e
-	l.len++
-	return e
+	return l.insertValue(v, mark)
 }
 
 func (l *List) InsertBefore(v interface{}, mark *Element) *Element {
-	if mark.belongsTo != l {
+	if !l.owns(mark) {
 		return nil
 	}
-	e := &Element{
-		prev:      mark.prev,
-		next:      mark,
-		belongsTo: l,
-		Value:     v,
-	}
-	e.prev.next = e
-	e.next.prev = e
-	e.belongsTo = l
-	l.len++
-	return e
+	return l.insertValue(v, mark.prev)
 }
 
 func (l *List) Len() int {
@@ -125,7 +143,7 @@ func (l *List) Len() int {
 }
 
 func (l *List) MoveAfter(e, mark *Element) {
-	if e.belongsTo != l || mark.belongsTo != l || e == mark {
+	if !l.owns(e) || !l.owns(mark) || e == mark {
 		return
 	}
 	e.prev.next = e.next
@@ -137,7 +155,7 @@ func (l *List) MoveAfter(e, mark *Element) {
 }
 
 func (l *List) MoveBefore(e, mark *Element) {
-	if e.belongsTo != l || mark.belongsTo != l || e == mark {
+	if !l.owns(e) || !l.owns(mark) || e == mark {
 		return
 	}
 	e.prev.next = e.next
@@ -150,7 +168,7 @@ func (l *List) MoveBefore(e, mark *Element) {
 
 func (l *List) MoveToBack(e *Element) {
 	l.checkAndInit()
-	if e.belongsTo != l || e == l.root.prev {
+	if !l.owns(e) || e == l.root.prev {
 		return
 	}
 	e.prev.next = e.next
@@ -163,7 +181,7 @@ func (l *List) MoveToBack(e *Element) {
 
 func (l *List) MoveToFront(e *Element) {
 	l.checkAndInit()
-	if e.belongsTo != l || e == l.root.next {
+	if !l.owns(e) || e == l.root.next {
 		return
 	}
 	e.prev.next = e.next
@@ -176,86 +194,82 @@ func (l *List) MoveToFront(e *Element) {
 
 func (l *List) PushBack(v interface{}) *Element {
 	l.checkAndInit()
-	e := &Element{
-		prev:      l.root.prev,
-		next:      &l.root,
-		belongsTo: l,
-		Value:     v,
-	}
-	e.prev.next = e
-	e.next.prev = e
-	l.len++
-	return e
+	return l.insertValue(v, l.root.prev)
 }
 
+// PushBackList inserts a copy of other at the back of l, other may be l itself
 func (l *List) PushBackList(other *List) {
 	l.checkAndInit()
-	if other.len == 0 {
-		return
-	}
-	tail := l.root.prev
-
-	stop := tail
-
-	for e := other.Front(); e != stop && e != nil; e = e.Next() {
-		e2 := &Element{
-			prev:      tail,
-			next:      tail.next,
-			belongsTo: l,
-			Value:     e.Value,
-		}
-		tail.next = e2
-		tail = tail.next
-		if e == stop {
-			break
-		}
+	// the length is taken first, so that appending l to itself stops after the original elements
+	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
+		l.insertValue(e.Value, l.root.prev)
 	}
-	l.root.prev = tail
-	l.len += other.len
 }
 
 func (l *List) PushFront(v interface{}) *Element {
 	l.checkAndInit()
-	e := &Element{
-		prev:      &l.root,
-		next:      l.root.next,
-		belongsTo: l,
-		Value:     v,
+	return l.insertValue(v, &l.root)
+}
+
+// PushFrontList inserts a copy of other at the front of l, other may be l itself
+func (l *List) PushFrontList(other *List) {
+	l.checkAndInit()
+	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
+		l.insertValue(e.Value, &l.root)
+	}
+}
+
+func (l *List) insertValue(v interface{}, at *Element) *Element {
+	var e *Element
+	if l.pool != nil {
+		e = l.pool.get()
+	} else {
+		e = &Element{}
 	}
+	e.prev = at
+	e.next = at.next
+	e.belongsTo = l.owner
+	e.Value = v
 	e.prev.next = e
 	e.next.prev = e
 	l.len++
 	return e
 }
 
-func (l *List) PushFrontList(other *List) {
+// MoveBackList moves all elements of other to the back of l in O(1), other is left empty
+// the elements keep their identity and belong to l afterwards,
+// finding the list of an element takes O(log k) after k lists were moved into one another, see package ownership
+func (l *List) MoveBackList(other *List) {
+	l.checkAndInit()
+	l.splice(other, l.root.prev)
+}
+
+// MoveFrontList moves all elements of other to the front of l in O(1), other is left empty
+func (l *List) MoveFrontList(other *List) {
 	l.checkAndInit()
-	if other.len == 0 {
+	l.splice(other, &l.root)
+}
+
+// splice links the elements of other after at and hands them over to l
+func (l *List) splice(other *List, at *Element) {
+	if other == l || other.Len() == 0 {
 		return
 	}
-	head := l.root.next
-	stop := head
-
-	for e := other.Back(); e != nil; e = e.Prev() {
-		e2 := &Element{
-			prev:      head.prev,
-			next:      head,
-			belongsTo: l,
-			Value:     e.Value,
-		}
-		head.prev = e2
-		head = head.prev
-		if e == stop {
-			break
-		}
-	}
-	l.root.next = head
+	first, last := other.root.next, other.root.prev
+	first.prev = at
+	last.next = at.next
+	at.next.prev = last
+	at.next = first
 	l.len += other.len
+
+	l.owner = l.owner.Merge(other.owner)
+	other.owner = nil
+	other.Init()
 }
 
 func (l *List) Remove(e *Element) interface{} {
 	l.checkAndInit()
-	if e.belongsTo != l {
+	if !l.owns(e) {
 		return nil
 	}
 	e.prev.next = e.next
@@ -266,5 +280,8 @@ func (l *List) Remove(e *Element) interface{} {
 	value := e.Value
 	//e.Value = nil // should not set to nil, see https://groups.google.com/forum/#!topic/golang-nuts/HGCY7IanlvU
 	l.len--
+	if l.pool != nil {
+		l.pool.put(e)
+	}
 	return value
 }
@@ -0,0 +1,498 @@
+package list
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ IList = (*List)(nil)
+
+// values returns the values of l front to back, and checks the links both ways
+func values(t *testing.T, l *List) []interface{} {
+	res := []interface{}{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		assert.Equal(t, l, e.list())
+		res = append(res, e.Value)
+	}
+	assert.Equal(t, l.Len(), len(res))
+
+	back := []interface{}{}
+	for e := l.Back(); e != nil; e = e.Prev() {
+		back = append([]interface{}{e.Value}, back...)
+	}
+	assert.Equal(t, res, back)
+	return res
+}
+
+func ints(xs ...int) []interface{} {
+	res := make([]interface{}, len(xs))
+	for i, x := range xs {
+		res[i] = x
+	}
+	return res
+}
+
+func listOf(xs ...int) *List {
+	l := New()
+	for _, x := range xs {
+		l.PushBack(x)
+	}
+	return l
+}
+
+func TestList_PushBackList(t *testing.T) {
+	l := listOf(1, 2)
+	other := listOf(3, 4)
+
+	l.PushBackList(other)
+	assert.Equal(t, ints(1, 2, 3, 4), values(t, l))
+	assert.Equal(t, ints(3, 4), values(t, other))
+
+	// the copies are linked into l only
+	l.Remove(l.Back())
+	l.PushBack(5)
+	assert.Equal(t, ints(1, 2, 3, 5), values(t, l))
+	assert.Equal(t, ints(3, 4), values(t, other))
+
+	l.PushBackList(l)
+	assert.Equal(t, ints(1, 2, 3, 5, 1, 2, 3, 5), values(t, l))
+
+	var empty List
+	l.PushBackList(&empty)
+	assert.Equal(t, 8, l.Len())
+	empty.PushBackList(other)
+	assert.Equal(t, ints(3, 4), values(t, &empty))
+}
+
+func TestList_PushFrontList(t *testing.T) {
+	l := listOf(3, 4)
+	other := listOf(1, 2)
+
+	l.PushFrontList(other)
+	assert.Equal(t, ints(1, 2, 3, 4), values(t, l))
+	assert.Equal(t, ints(1, 2), values(t, other))
+
+	l.PushFrontList(l)
+	assert.Equal(t, ints(1, 2, 3, 4, 1, 2, 3, 4), values(t, l))
+
+	var empty List
+	empty.PushFrontList(other)
+	assert.Equal(t, ints(1, 2), values(t, &empty))
+}
+
+func TestList_MoveBackList(t *testing.T) {
+	l := listOf(1, 2)
+	other := listOf(3, 4)
+	three := other.Front()
+
+	l.MoveBackList(other)
+	assert.Equal(t, ints(1, 2, 3, 4), values(t, l))
+	assert.Equal(t, ints(), values(t, other))
+
+	// the moved elements now belong to l, and no longer to other
+	l.MoveToFront(three)
+	assert.Equal(t, ints(3, 1, 2, 4), values(t, l))
+	other.MoveToBack(three)
+	assert.Nil(t, other.InsertAfter(0, three))
+	assert.Nil(t, other.Remove(three))
+	assert.Equal(t, ints(3, 1, 2, 4), values(t, l))
+
+	// other is still usable, and its new elements are not l's
+	five := other.PushBack(5)
+	l.MoveToFront(five)
+	assert.Equal(t, ints(5), values(t, other))
+	assert.Equal(t, 3, l.Remove(three))
+	assert.Equal(t, ints(1, 2, 4), values(t, l))
+
+	l.MoveBackList(l)
+	l.MoveBackList(New())
+	assert.Equal(t, ints(1, 2, 4), values(t, l))
+
+	var empty List
+	empty.MoveBackList(l)
+	assert.Equal(t, ints(1, 2, 4), values(t, &empty))
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestList_MoveFrontList(t *testing.T) {
+	l := listOf(3, 4)
+	other := listOf(1, 2)
+	l.MoveFrontList(other)
+	assert.Equal(t, ints(1, 2, 3, 4), values(t, l))
+	assert.Equal(t, ints(), values(t, other))
+}
+
+func TestList_MoveListChain(t *testing.T) {
+	a, b, c := listOf(1), listOf(2), listOf(3)
+	one, two := a.Front(), b.Front()
+
+	b.MoveBackList(a)
+	c.MoveBackList(b)
+	assert.Equal(t, ints(3, 2, 1), values(t, c))
+	c.MoveToBack(two)
+	c.MoveToFront(one)
+	assert.Equal(t, ints(1, 3, 2), values(t, c))
+
+	// elements of a list which is re-initialized are disowned
+	c.Init()
+	c.MoveToFront(two)
+	assert.Nil(t, c.Remove(one))
+	assert.Nil(t, one.Next())
+	assert.Equal(t, ints(), values(t, c))
+	c.PushBack(4)
+	assert.Equal(t, ints(4), values(t, c))
+}
+
+// walking a list is read-only even when its elements were moved in from other lists, run with -race
+func TestList_ConcurrentReaders(t *testing.T) {
+	a, b, c := listOf(1, 2), listOf(3, 4), listOf(5)
+	b.MoveBackList(c)
+	a.MoveBackList(b)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				n := 0
+				for e := a.Front(); e != nil; e = e.Next() {
+					n++
+				}
+				for e := a.Back(); e != nil; e = e.Prev() {
+					n++
+				}
+				assert.Equal(t, 10, n)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the paths are shortened by the next modification instead
+	e := a.Back()
+	assert.NotSame(t, a.owner, e.belongsTo)
+	a.MoveToFront(e)
+	assert.Same(t, a.owner, e.belongsTo)
+	assert.Equal(t, ints(5, 1, 2, 3, 4), values(t, a))
+}
+
+func TestList_Remove(t *testing.T) {
+	l := listOf(1, 2, 3)
+	two := l.Front().Next()
+	assert.Equal(t, 2, l.Remove(two))
+	assert.Nil(t, l.Remove(two))
+	assert.Nil(t, two.Next())
+	assert.Nil(t, l.InsertAfter(0, two))
+	assert.Equal(t, ints(1, 3), values(t, l))
+
+	l.InsertBefore(0, l.Front())
+	l.InsertAfter(2, l.Front().Next())
+	assert.Equal(t, ints(0, 1, 2, 3), values(t, l))
+}
+
+func TestList_All(t *testing.T) {
+	l := listOf(1, 2, 3, 4)
+
+	var res []interface{}
+	for v := range l.All() {
+		res = append(res, v)
+	}
+	assert.Equal(t, ints(1, 2, 3, 4), res)
+
+	res = nil
+	for v := range l.Backward() {
+		if v == 2 {
+			break
+		}
+		res = append(res, v)
+	}
+	assert.Equal(t, ints(4, 3), res)
+
+	var empty List
+	for range empty.All() {
+		t.Fatal("empty list yielded a value")
+	}
+	for range empty.Backward() {
+		t.Fatal("empty list yielded a value")
+	}
+}
+
+type pair struct {
+	key, seq int
+}
+
+func TestList_Sort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1000} {
+		l := New()
+		expected := make([]interface{}, n)
+		elems := make(map[*Element]pair, n)
+		for i := 0; i < n; i++ {
+			p := pair{key: rnd.Intn(10), seq: i}
+			expected[i] = p
+			elems[l.PushBack(p)] = p
+		}
+		byKey := func(a, b interface{}) bool {
+			return a.(pair).key < b.(pair).key
+		}
+		sort.SliceStable(expected, func(i, j int) bool {
+			return byKey(expected[i], expected[j])
+		})
+
+		l.Sort(byKey)
+		assert.Equal(t, expected, values(t, l))
+		// the elements were relinked, not reallocated
+		for e, p := range elems {
+			assert.Equal(t, p, e.Value)
+			assert.Equal(t, l, e.list())
+		}
+	}
+
+	var empty List
+	empty.Sort(func(a, b interface{}) bool { return false })
+	assert.Equal(t, ints(), values(t, &empty))
+}
+
+func TestList_Reverse(t *testing.T) {
+	l := listOf(1, 2, 3, 4)
+	front := l.Front()
+	l.Reverse()
+	assert.Equal(t, ints(4, 3, 2, 1), values(t, l))
+	assert.Equal(t, front, l.Back())
+	l.MoveToFront(front)
+	assert.Equal(t, ints(1, 4, 3, 2), values(t, l))
+
+	l = listOf(1)
+	l.Reverse()
+	assert.Equal(t, ints(1), values(t, l))
+	var empty List
+	empty.Reverse()
+	assert.Equal(t, ints(), values(t, &empty))
+}
+
+func TestList_Rotate(t *testing.T) {
+	l := listOf(1, 2, 3, 4, 5)
+	l.Rotate(2)
+	assert.Equal(t, ints(3, 4, 5, 1, 2), values(t, l))
+	l.Rotate(-1)
+	assert.Equal(t, ints(2, 3, 4, 5, 1), values(t, l))
+	l.Rotate(5)
+	assert.Equal(t, ints(2, 3, 4, 5, 1), values(t, l))
+	l.Rotate(14)
+	assert.Equal(t, ints(1, 2, 3, 4, 5), values(t, l))
+	l.Rotate(-7)
+	assert.Equal(t, ints(4, 5, 1, 2, 3), values(t, l))
+
+	var empty List
+	empty.Rotate(3)
+	assert.Equal(t, ints(), values(t, &empty))
+}
+
+func TestList_SplitAt(t *testing.T) {
+	l := listOf(1, 2, 3, 4, 5)
+	four := l.Back().Prev()
+	rest := l.SplitAt(3)
+	assert.Equal(t, ints(1, 2, 3), values(t, l))
+	assert.Equal(t, ints(4, 5), values(t, rest))
+
+	// the moved elements belong to the new list only
+	l.MoveToFront(four)
+	assert.Nil(t, l.Remove(four))
+	rest.MoveToBack(four)
+	assert.Equal(t, ints(5, 4), values(t, rest))
+	l.PushBack(6)
+	assert.Equal(t, ints(1, 2, 3, 6), values(t, l))
+
+	all := l.SplitAt(-1)
+	assert.Equal(t, ints(), values(t, l))
+	assert.Equal(t, ints(1, 2, 3, 6), values(t, all))
+	assert.Equal(t, ints(), values(t, all.SplitAt(10)))
+	assert.Equal(t, ints(1, 2, 3, 6), values(t, all))
+}
+
+func TestList_Functional(t *testing.T) {
+	l := listOf(1, 2, 3, 4, 5)
+	isEven := func(v interface{}) bool {
+		return v.(int)%2 == 0
+	}
+
+	assert.Equal(t, 2, l.Find(isEven).Value)
+	assert.Nil(t, l.Find(func(v interface{}) bool { return v.(int) > 5 }))
+
+	evens := l.Filter(isEven)
+	assert.Equal(t, ints(2, 4), values(t, evens))
+	squares := l.Map(func(v interface{}) interface{} {
+		return v.(int) * v.(int)
+	})
+	assert.Equal(t, ints(1, 4, 9, 16, 25), values(t, squares))
+	sum := l.Reduce(0, func(acc, v interface{}) interface{} {
+		return acc.(int) + v.(int)
+	})
+	assert.Equal(t, 15, sum)
+
+	// the results are new lists, l is untouched
+	assert.Equal(t, ints(1, 2, 3, 4, 5), values(t, l))
+	evens.Remove(evens.Front())
+	assert.Equal(t, 5, l.Len())
+}
+
+// pooledValues returns the values of pl front to back, following the handles both ways
+func pooledValues(t *testing.T, pl *PooledList) []interface{} {
+	res := []interface{}{}
+	for h := pl.Front(); h.Valid(); h = h.Next() {
+		res = append(res, h.Value())
+	}
+	assert.Equal(t, pl.Len(), len(res))
+
+	back := []interface{}{}
+	for h := pl.Back(); h.Valid(); h = h.Prev() {
+		back = append([]interface{}{h.Value()}, back...)
+	}
+	assert.Equal(t, res, back)
+	return res
+}
+
+func TestPooledList(t *testing.T) {
+	p := NewPool(2)
+	l := NewPooled(p)
+	h1 := l.PushBack(1)
+	h2 := l.PushBack(2)
+	h3 := l.PushBack(3)
+	assert.Equal(t, ints(1, 2, 3), pooledValues(t, l))
+
+	l.MoveToFront(h3)
+	l.MoveAfter(h1, h2)
+	assert.Equal(t, ints(3, 2, 1), pooledValues(t, l))
+	assert.True(t, h2.SetValue(20))
+	assert.Equal(t, 20, l.Remove(h2))
+	assert.False(t, h2.Valid())
+	assert.Nil(t, h2.e.Value, "a recycled element does not keep its value alive")
+
+	// the next push reuses the element of h2, which is still rejected
+	h4 := l.PushFront(4)
+	assert.Same(t, h2.e, h4.e)
+	assert.False(t, h2.Valid())
+	assert.Nil(t, h2.Value())
+	assert.False(t, h2.SetValue(0))
+	assert.Equal(t, Handle{}, h2.Next())
+	assert.Equal(t, Handle{}, h2.Prev())
+	l.MoveToBack(h2)
+	l.MoveBefore(h2, h3)
+	l.MoveAfter(h3, h2)
+	assert.Equal(t, Handle{}, l.InsertAfter(0, h2))
+	assert.Nil(t, l.Remove(h2))
+	assert.Equal(t, ints(4, 3, 1), pooledValues(t, l))
+	assert.Equal(t, 4, h4.Value())
+
+	// a pool may be shared, a stale handle is rejected by the list which reused its element too
+	other := NewPooled(p)
+	assert.Equal(t, 1, l.Remove(h1))
+	h5 := other.PushBack(5)
+	assert.Same(t, h1.e, h5.e)
+	l.MoveToFront(h1)
+	other.MoveToFront(h1)
+	assert.Nil(t, other.Remove(h1))
+	assert.Equal(t, ints(4, 3), pooledValues(t, l))
+	assert.Equal(t, ints(5), pooledValues(t, other))
+
+	// handles of another list are ignored
+	l.MoveBefore(h5, h4)
+	assert.Nil(t, l.Remove(h5))
+	assert.Equal(t, Handle{}, l.InsertBefore(0, h5))
+	assert.Nil(t, l.Remove(Handle{}))
+
+	l.Init()
+	assert.False(t, h4.Valid())
+	assert.Equal(t, 0, l.Len())
+	assert.Equal(t, ints(), pooledValues(t, l))
+}
+
+func TestPooledList_Churn(t *testing.T) {
+	l := NewPooled(NewPool(16))
+	for i := 0; i < 100; i++ {
+		l.PushBack(i)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := 0; i < 100; i++ {
+			l.Remove(l.Front())
+			l.PushBack(i)
+		}
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, 100, l.Len())
+}
+
+func benchmarkChurn(b *testing.B, l *List) {
+	b.ReportAllocs()
+	for i := 0; i < 1000; i++ {
+		l.PushFront(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Remove(l.Back())
+		l.PushFront(i)
+	}
+}
+
+func BenchmarkList_Churn(b *testing.B) {
+	benchmarkChurn(b, New())
+}
+
+func BenchmarkPooledList_Churn(b *testing.B) {
+	b.ReportAllocs()
+	l := NewPooled(nil)
+	for i := 0; i < 1000; i++ {
+		l.PushFront(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Remove(l.Back())
+		l.PushFront(i)
+	}
+}
+
+func benchmarkFill(b *testing.B, newList func() *List) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := newList()
+		for j := 0; j < 1000; j++ {
+			l.PushBack(j)
+		}
+	}
+}
+
+func BenchmarkList_Fill(b *testing.B) {
+	benchmarkFill(b, New)
+}
+
+func BenchmarkPooledList_Fill(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := NewPooled(nil)
+		for j := 0; j < 1000; j++ {
+			l.PushBack(j)
+		}
+	}
+}
+
+// BenchmarkList_IterateAfterSplices walks a list of 1000 elements, each moved in through its own chain of 1000 lists
+func BenchmarkList_IterateAfterSplices(b *testing.B) {
+	l := New()
+	for i := 0; i < 1000; i++ {
+		l.PushBack(i)
+	}
+	for i := 0; i < 1000; i++ {
+		next := New()
+		next.MoveBackList(l)
+		l = next
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for e := l.Front(); e != nil; e = e.Next() {
+		}
+	}
+}
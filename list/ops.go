@@ -0,0 +1,180 @@
+package list
+
+// Sort sorts the list by less with a bottom-up merge sort in O(NlogN) time and O(1) extra space
+// the sort is stable, and the elements are relinked rather than copied, so they keep their values and their list
+func (l *List) Sort(less func(a, b interface{}) bool) {
+	l.checkAndInit()
+	if l.len < 2 {
+		return
+	}
+
+	// the runs are merged along the next pointers only, prev pointers are fixed at the end
+	head := l.root.next
+	l.root.prev.next = nil
+	for size := 1; size < l.len; size *= 2 {
+		var merged, tail *Element
+		appendTail := func(e *Element) {
+			if tail == nil {
+				merged = e
+			} else {
+				tail.next = e
+			}
+			tail = e
+		}
+
+		p := head
+		for p != nil {
+			q, psize := p, 0
+			for psize < size && q != nil {
+				q = q.next
+				psize++
+			}
+			qsize := size
+			for psize > 0 || (qsize > 0 && q != nil) {
+				// an element of the right run only goes first when it is strictly less, which keeps the sort stable
+				if psize > 0 && (qsize == 0 || q == nil || !less(q.Value, p.Value)) {
+					appendTail(p)
+					p = p.next
+					psize--
+				} else {
+					appendTail(q)
+					q = q.next
+					qsize--
+				}
+			}
+			p = q
+		}
+		tail.next = nil
+		head = merged
+	}
+
+	prev := &l.root
+	for e := head; e != nil; e = e.next {
+		e.prev = prev
+		prev.next = e
+		prev = e
+	}
+	prev.next = &l.root
+	l.root.prev = prev
+}
+
+// Reverse reverses the order of the elements in O(N)
+func (l *List) Reverse() {
+	l.checkAndInit()
+	e := &l.root
+	for {
+		e.prev, e.next = e.next, e.prev
+		e = e.prev
+		if e == &l.root {
+			return
+		}
+	}
+}
+
+// at returns the i-th element, walking from the closer end, 0 <= i < l.len must hold
+func (l *List) at(i int) *Element {
+	if i < l.len/2 {
+		e := l.root.next
+		for ; i > 0; i-- {
+			e = e.next
+		}
+		return e
+	}
+	e := l.root.prev
+	for i = l.len - 1 - i; i > 0; i-- {
+		e = e.prev
+	}
+	return e
+}
+
+// Rotate moves the first n elements to the back, a negative n moves the last -n elements to the front
+// only the root is relinked, walking to the new front element costs O(min(n, N-n))
+func (l *List) Rotate(n int) {
+	l.checkAndInit()
+	if l.len < 2 {
+		return
+	}
+	n %= l.len
+	if n < 0 {
+		n += l.len
+	}
+	if n == 0 {
+		return
+	}
+
+	front := l.at(n)
+	l.root.prev.next = l.root.next
+	l.root.next.prev = l.root.prev
+	l.root.prev = front.prev
+	l.root.next = front
+	front.prev.next = &l.root
+	front.prev = &l.root
+}
+
+// SplitAt keeps the first i elements in l and moves the rest to a new list, which is returned
+// i is clamped to [0, l.Len()], the moved elements are re-owned one by one, so it costs O(N)
+func (l *List) SplitAt(i int) *List {
+	l.checkAndInit()
+	res := New()
+	res.pool = l.pool
+	if i < 0 {
+		i = 0
+	}
+	if i >= l.len {
+		return res
+	}
+
+	first, last := l.at(i), l.root.prev
+	first.prev.next = &l.root
+	l.root.prev = first.prev
+
+	first.prev = &res.root
+	last.next = &res.root
+	res.root.next = first
+	res.root.prev = last
+	res.len = l.len - i
+	l.len = i
+	for e := first; e != &res.root; e = e.next {
+		e.belongsTo = res.owner
+	}
+	return res
+}
+
+// Find returns the first element whose value satisfies pred, or nil if there is none
+func (l *List) Find(pred func(v interface{}) bool) *Element {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Filter returns a new list holding the values which satisfy pred, in the same order
+func (l *List) Filter(pred func(v interface{}) bool) *List {
+	res := New()
+	for e := l.Front(); e != nil; e = e.Next() {
+		if pred(e.Value) {
+			res.PushBack(e.Value)
+		}
+	}
+	return res
+}
+
+// Map returns a new list holding f of every value, in the same order
+func (l *List) Map(f func(v interface{}) interface{}) *List {
+	res := New()
+	for e := l.Front(); e != nil; e = e.Next() {
+		res.PushBack(f(e.Value))
+	}
+	return res
+}
+
+// Reduce folds the values from front to back, starting with init
+func (l *List) Reduce(init interface{}, f func(acc, v interface{}) interface{}) interface{} {
+	acc := init
+	for e := l.Front(); e != nil; e = e.Next() {
+		acc = f(acc, e.Value)
+	}
+	return acc
+}
@@ -0,0 +1,118 @@
+// A persistent singly linked list, the immutable counterpart of package list
+// every operation returns a new version and leaves the old one intact, the versions share their common tails
+// cells are never modified after they are created, so any number of goroutines may read any version without locks
+package persistent
+
+import "iter"
+
+type cell[T any] struct {
+	value T
+	next  *cell[T]
+}
+
+// List is one version of a list, it is a small value which is meant to be copied
+// the zero value is the empty list
+type List[T any] struct {
+	head *cell[T]
+	len  int
+}
+
+// Of returns the list holding vs, with vs[0] at the front
+func Of[T any](vs ...T) List[T] {
+	var l List[T]
+	for i := len(vs) - 1; i >= 0; i-- {
+		l = l.Push(vs[i])
+	}
+	return l
+}
+
+func (l List[T]) Len() int {
+	return l.len
+}
+
+func (l List[T]) IsEmpty() bool {
+	return l.len == 0
+}
+
+// Push returns the list with v in front of l in O(1), the result shares all cells of l
+func (l List[T]) Push(v T) List[T] {
+	return List[T]{
+		head: &cell[T]{value: v, next: l.head},
+		len:  l.len + 1,
+	}
+}
+
+// Peek returns the front value, ok is false when l is empty
+func (l List[T]) Peek() (v T, ok bool) {
+	if l.head == nil {
+		return v, false
+	}
+	return l.head.value, true
+}
+
+// Pop returns the front value and the list behind it in O(1), ok is false when l is empty
+func (l List[T]) Pop() (v T, rest List[T], ok bool) {
+	if l.head == nil {
+		return v, l, false
+	}
+	return l.head.value, List[T]{head: l.head.next, len: l.len - 1}, true
+}
+
+// Drop returns l without its first n values in O(n), it is empty when n >= l.Len()
+func (l List[T]) Drop(n int) List[T] {
+	for ; n > 0 && l.head != nil; n-- {
+		l = List[T]{head: l.head.next, len: l.len - 1}
+	}
+	return l
+}
+
+// At returns the i-th value in O(i), ok is false when i is out of [0, Len())
+func (l List[T]) At(i int) (v T, ok bool) {
+	if i < 0 || i >= l.len {
+		return v, false
+	}
+	return l.Drop(i).Peek()
+}
+
+// Reverse returns the values of l in reverse order, it builds l.Len() new cells
+func (l List[T]) Reverse() List[T] {
+	var res List[T]
+	for c := l.head; c != nil; c = c.next {
+		res = res.Push(c.value)
+	}
+	return res
+}
+
+// Concat returns l followed by other, the cells of l are copied and the result shares all cells of other
+func (l List[T]) Concat(other List[T]) List[T] {
+	if other.len == 0 {
+		return l
+	}
+	res := other
+	for _, v := range l.Reverse().All() {
+		res = res.Push(v)
+	}
+	return res
+}
+
+// All returns an iterator over the positions and values from front to back
+func (l List[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for c := l.head; c != nil; c = c.next {
+			if !yield(i, c.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Slice returns the values in a new slice, from front to back
+func (l List[T]) Slice() []T {
+	res := make([]T, 0, l.len)
+	for c := l.head; c != nil; c = c.next {
+		res = append(res, c.value)
+	}
+	return res
+}
@@ -0,0 +1,105 @@
+package persistent
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	var empty List[int]
+	assert.True(t, empty.IsEmpty())
+	_, ok := empty.Peek()
+	assert.False(t, ok)
+	_, rest, ok := empty.Pop()
+	assert.False(t, ok)
+	assert.True(t, rest.IsEmpty())
+
+	l1 := empty.Push(1)
+	l2 := l1.Push(2)
+	l3 := l2.Push(3)
+	assert.Equal(t, []int{3, 2, 1}, l3.Slice())
+	// the old versions are untouched
+	assert.Equal(t, []int{2, 1}, l2.Slice())
+	assert.Equal(t, []int{1}, l1.Slice())
+	assert.Equal(t, []int{}, empty.Slice())
+
+	v, rest, ok := l3.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, l2, rest)
+	assert.Equal(t, 3, l3.Len())
+
+	// two versions pushed onto the same list share it
+	a := l2.Push(10)
+	b := l2.Push(20)
+	assert.Equal(t, []int{10, 2, 1}, a.Slice())
+	assert.Equal(t, []int{20, 2, 1}, b.Slice())
+	assert.Same(t, a.head.next, b.head.next)
+}
+
+func TestList_Operations(t *testing.T) {
+	l := Of(1, 2, 3, 4)
+	assert.Equal(t, []int{1, 2, 3, 4}, l.Slice())
+	assert.Equal(t, 4, l.Len())
+
+	v, ok := l.At(2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	_, ok = l.At(4)
+	assert.False(t, ok)
+
+	assert.Equal(t, []int{3, 4}, l.Drop(2).Slice())
+	assert.Equal(t, 2, l.Drop(2).Len())
+	assert.Same(t, l.head.next.next, l.Drop(2).head)
+	assert.True(t, l.Drop(10).IsEmpty())
+
+	assert.Equal(t, []int{4, 3, 2, 1}, l.Reverse().Slice())
+
+	tail := Of(5, 6)
+	both := l.Concat(tail)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, both.Slice())
+	assert.Equal(t, 6, both.Len())
+	assert.Same(t, tail.head, both.Drop(4).head)
+	assert.Equal(t, l, l.Concat(List[int]{}))
+	assert.Equal(t, []int{1, 2, 3, 4}, l.Slice())
+
+	var got []int
+	for i, v := range l.All() {
+		if i == 2 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{1, 2}, got)
+}
+
+// TestList_ConcurrentReaders shares versions between goroutines, which push and pop without any locks
+// run with -race to check that no version is modified
+func TestList_ConcurrentReaders(t *testing.T) {
+	base := Of(1, 2, 3)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			l := base
+			for i := 0; i < 1000; i++ {
+				l = l.Push(g*1000 + i)
+				if i%3 == 0 {
+					_, l, _ = l.Pop()
+				}
+				sum := 0
+				for _, v := range base.All() {
+					sum += v
+				}
+				assert.Equal(t, 6, sum)
+			}
+			assert.Equal(t, base.Slice(), l.Drop(l.Len()-3).Slice())
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(t, []int{1, 2, 3}, base.Slice())
+}
@@ -0,0 +1,220 @@
+package list
+
+import "iter"
+
+// DefaultChunkSize is the number of elements a Pool allocates at once unless told otherwise
+const DefaultChunkSize = 64
+
+// Pool recycles the elements of the lists created by NewPooled, so that a list with a steady size stops allocating
+// new elements are carved out of chunks, which turns many small allocations into a few large ones
+// a Pool may be shared by several lists, like a List it is not safe for concurrent use
+type Pool struct {
+	free      *Element // linked through next
+	chunk     []Element
+	chunkSize int
+}
+
+// NewPool returns an empty pool which allocates chunkSize elements at a time
+func NewPool(chunkSize int) *Pool {
+	if chunkSize < 1 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Pool{chunkSize: chunkSize}
+}
+
+func (p *Pool) get() *Element {
+	if e := p.free; e != nil {
+		p.free = e.next
+		e.next = nil
+		return e
+	}
+	if len(p.chunk) == 0 {
+		p.chunk = make([]Element, p.chunkSize)
+	}
+	e := &p.chunk[0]
+	p.chunk = p.chunk[1:]
+	return e
+}
+
+// put takes back an element which has been unlinked and disowned by Remove
+// the generation of the element moves on, which invalidates every handle taken before
+func (p *Pool) put(e *Element) {
+	e.gen++
+	e.Value = nil // unlike a plain Remove, the pool would otherwise keep the value alive
+	e.prev = nil
+	e.next = p.free
+	p.free = e
+}
+
+// Handle designates an element of a PooledList, it stands in for the element pointers of a List
+// a recycled element is handed out again by its pool, so a pointer to it could designate a new element after Remove,
+// while a handle remembers the generation of the element it was taken at, and every operation rejects it once the element is recycled
+// the zero Handle designates nothing
+type Handle struct {
+	e   *Element
+	gen uint64
+}
+
+func handleOf(e *Element) Handle {
+	if e == nil {
+		return Handle{}
+	}
+	return Handle{e: e, gen: e.gen}
+}
+
+// element returns the element designated by h, nil when h is stale or its element belongs to no list
+func (h Handle) element() *Element {
+	if h.e == nil || h.e.gen != h.gen || h.e.list() == nil {
+		return nil
+	}
+	return h.e
+}
+
+// Valid returns whether h designates an element of a list
+func (h Handle) Valid() bool {
+	return h.element() != nil
+}
+
+// Value returns the value of the element designated by h, nil when h is not valid
+func (h Handle) Value() interface{} {
+	if e := h.element(); e != nil {
+		return e.Value
+	}
+	return nil
+}
+
+// SetValue replaces the value of the element designated by h, it returns false and does nothing when h is not valid
+func (h Handle) SetValue(v interface{}) bool {
+	e := h.element()
+	if e == nil {
+		return false
+	}
+	e.Value = v
+	return true
+}
+
+// Next returns the handle of the next element, the zero Handle when h is the last one or not valid
+func (h Handle) Next() Handle {
+	if e := h.element(); e != nil {
+		return handleOf(e.Next())
+	}
+	return Handle{}
+}
+
+// Prev returns the handle of the previous element, the zero Handle when h is the first one or not valid
+func (h Handle) Prev() Handle {
+	if e := h.element(); e != nil {
+		return handleOf(e.Prev())
+	}
+	return Handle{}
+}
+
+// PooledList is a List whose elements come from a Pool and go back to it on Remove, its elements are designated by handles
+// a handle which does not designate an element of the list is ignored, like an element of another list by a List
+type PooledList struct {
+	l *List
+}
+
+// NewPooled returns an empty list which takes its elements from p, a nil p gives the list a pool of its own
+func NewPooled(p *Pool) *PooledList {
+	if p == nil {
+		p = NewPool(0)
+	}
+	l := New()
+	l.pool = p
+	return &PooledList{l: l}
+}
+
+// owned returns the element designated by h if it belongs to pl
+func (pl *PooledList) owned(h Handle) *Element {
+	e := h.element()
+	if e == nil || !pl.l.owns(e) {
+		return nil
+	}
+	return e
+}
+
+// Init clears the list, the handles of its elements are no longer valid
+// the elements are left to the garbage collector instead of the pool
+func (pl *PooledList) Init() *PooledList {
+	pl.l.Init()
+	return pl
+}
+
+func (pl *PooledList) Len() int {
+	return pl.l.Len()
+}
+
+func (pl *PooledList) Front() Handle {
+	return handleOf(pl.l.Front())
+}
+
+func (pl *PooledList) Back() Handle {
+	return handleOf(pl.l.Back())
+}
+
+func (pl *PooledList) PushFront(v interface{}) Handle {
+	return handleOf(pl.l.PushFront(v))
+}
+
+func (pl *PooledList) PushBack(v interface{}) Handle {
+	return handleOf(pl.l.PushBack(v))
+}
+
+func (pl *PooledList) InsertBefore(v interface{}, mark Handle) Handle {
+	m := pl.owned(mark)
+	if m == nil {
+		return Handle{}
+	}
+	return handleOf(pl.l.InsertBefore(v, m))
+}
+
+func (pl *PooledList) InsertAfter(v interface{}, mark Handle) Handle {
+	m := pl.owned(mark)
+	if m == nil {
+		return Handle{}
+	}
+	return handleOf(pl.l.InsertAfter(v, m))
+}
+
+func (pl *PooledList) MoveToFront(h Handle) {
+	if e := pl.owned(h); e != nil {
+		pl.l.MoveToFront(e)
+	}
+}
+
+func (pl *PooledList) MoveToBack(h Handle) {
+	if e := pl.owned(h); e != nil {
+		pl.l.MoveToBack(e)
+	}
+}
+
+func (pl *PooledList) MoveBefore(h, mark Handle) {
+	e, m := pl.owned(h), pl.owned(mark)
+	if e != nil && m != nil {
+		pl.l.MoveBefore(e, m)
+	}
+}
+
+func (pl *PooledList) MoveAfter(h, mark Handle) {
+	e, m := pl.owned(h), pl.owned(mark)
+	if e != nil && m != nil {
+		pl.l.MoveAfter(e, m)
+	}
+}
+
+// Remove removes the element designated by h and returns its value, the element goes back to the pool
+// nil is returned when h does not designate an element of the list
+func (pl *PooledList) Remove(h Handle) interface{} {
+	e := pl.owned(h)
+	if e == nil {
+		return nil
+	}
+	return pl.l.Remove(e)
+}
+
+// All returns an iterator over the values from front to back
+// the list must not be modified during the iteration
+func (pl *PooledList) All() iter.Seq[interface{}] {
+	return pl.l.All()
+}
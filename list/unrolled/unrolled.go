@@ -0,0 +1,260 @@
+// An unrolled linked list, each node holds a small array of values
+// scans touch one node per nodeCap values instead of one element per value as in package list,
+// and inserting or removing in the middle only shifts the values of one node
+package unrolled
+
+import (
+	"iter"
+	"slices"
+)
+
+const (
+	DefaultNodeCap = 64
+	minNodeCap     = 4
+)
+
+type node[T any] struct {
+	prev   *node[T]
+	next   *node[T]
+	values []T // 1 <= len(values) <= cap(values) == nodeCap
+}
+
+// List is a sequence of values with access by position
+// every node is kept at least half full, except when the list is too short for that,
+// so finding a position costs O(N/nodeCap) and inserting or removing costs O(N/nodeCap + nodeCap)
+// the zero value is an empty list with DefaultNodeCap values per node
+type List[T any] struct {
+	head    *node[T]
+	tail    *node[T]
+	len     int
+	nodeCap int
+}
+
+// New returns an empty list whose nodes hold up to nodeCap values
+func New[T any](nodeCap int) *List[T] {
+	if nodeCap < minNodeCap {
+		nodeCap = minNodeCap
+	}
+	return &List[T]{nodeCap: nodeCap}
+}
+
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+func (l *List[T]) capacity() int {
+	if l.nodeCap == 0 {
+		l.nodeCap = DefaultNodeCap
+	}
+	return l.nodeCap
+}
+
+func (l *List[T]) newNode() *node[T] {
+	return &node[T]{values: make([]T, 0, l.capacity())}
+}
+
+// insertNodeAfter links n after at, a nil at means the front
+func (l *List[T]) insertNodeAfter(n, at *node[T]) {
+	n.prev = at
+	if at == nil {
+		n.next = l.head
+		l.head = n
+	} else {
+		n.next = at.next
+		at.next = n
+	}
+	if n.next == nil {
+		l.tail = n
+	} else {
+		n.next.prev = n
+	}
+}
+
+func (l *List[T]) unlinkNode(n *node[T]) {
+	if n.prev == nil {
+		l.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev = nil
+	n.next = nil
+}
+
+// locate returns the node holding the i-th value and the offset in it, 0 <= i < l.len must hold
+// it walks from the closer end of the list
+func (l *List[T]) locate(i int) (*node[T], int) {
+	if i < l.len/2 {
+		n := l.head
+		for i >= len(n.values) {
+			i -= len(n.values)
+			n = n.next
+		}
+		return n, i
+	}
+	n := l.tail
+	i = l.len - i // distance from the back, counting the value itself
+	for i > len(n.values) {
+		i -= len(n.values)
+		n = n.prev
+	}
+	return n, len(n.values) - i
+}
+
+// At returns the i-th value, ok is false when i is out of [0, Len())
+func (l *List[T]) At(i int) (v T, ok bool) {
+	if i < 0 || i >= l.len {
+		return v, false
+	}
+	n, j := l.locate(i)
+	return n.values[j], true
+}
+
+// Set replaces the i-th value, it returns false when i is out of [0, Len())
+func (l *List[T]) Set(i int, v T) bool {
+	if i < 0 || i >= l.len {
+		return false
+	}
+	n, j := l.locate(i)
+	n.values[j] = v
+	return true
+}
+
+// Insert puts v at position i, shifting the values from i on, i must be in [0, Len()]
+// it returns false and does nothing when i is out of range
+func (l *List[T]) Insert(i int, v T) bool {
+	if i < 0 || i > l.len {
+		return false
+	}
+	var n *node[T]
+	var j int
+	switch {
+	case l.head == nil:
+		n = l.newNode()
+		l.insertNodeAfter(n, nil)
+	case i == l.len:
+		n, j = l.tail, len(l.tail.values)
+	default:
+		n, j = l.locate(i)
+	}
+
+	if len(n.values) == l.capacity() {
+		// split the full node in halves and insert into the half which holds position j
+		half := len(n.values) / 2
+		m := l.newNode()
+		m.values = append(m.values, n.values[half:]...)
+		clear(n.values[half:])
+		n.values = n.values[:half]
+		l.insertNodeAfter(m, n)
+		if j > half {
+			n, j = m, j-half
+		}
+	}
+	n.values = slices.Insert(n.values, j, v)
+	l.len++
+	return true
+}
+
+func (l *List[T]) PushBack(v T) {
+	l.Insert(l.len, v)
+}
+
+func (l *List[T]) PushFront(v T) {
+	l.Insert(0, v)
+}
+
+// Remove deletes and returns the i-th value, ok is false when i is out of [0, Len())
+func (l *List[T]) Remove(i int) (v T, ok bool) {
+	if i < 0 || i >= l.len {
+		return v, false
+	}
+	n, j := l.locate(i)
+	v = n.values[j]
+	n.values = slices.Delete(n.values, j, j+1)
+	l.len--
+	l.rebalance(n)
+	return v, true
+}
+
+// rebalance restores the occupancy of n after a removal
+// an underfull node is merged with a neighbour when they fit in one node, otherwise it takes values from it
+func (l *List[T]) rebalance(n *node[T]) {
+	if len(n.values) == 0 {
+		l.unlinkNode(n)
+		return
+	}
+	half := l.capacity() / 2
+	if len(n.values) >= half {
+		return
+	}
+
+	left, right := n, n.next
+	if right == nil {
+		left, right = n.prev, n
+	}
+	if left == nil {
+		// n is the only node
+		return
+	}
+	if len(left.values)+len(right.values) <= l.capacity() {
+		left.values = append(left.values, right.values...)
+		clear(right.values)
+		l.unlinkNode(right)
+		return
+	}
+
+	// share the values evenly, so both nodes are at least half full
+	total := len(left.values) + len(right.values)
+	if left == n {
+		move := total/2 - len(left.values)
+		left.values = append(left.values, right.values[:move]...)
+		right.values = slices.Delete(right.values, 0, move)
+	} else {
+		move := total/2 - len(right.values)
+		right.values = slices.Insert(right.values, 0, left.values[len(left.values)-move:]...)
+		clear(left.values[len(left.values)-move:])
+		left.values = left.values[:len(left.values)-move]
+	}
+}
+
+// Clear removes all values
+func (l *List[T]) Clear() {
+	l.head = nil
+	l.tail = nil
+	l.len = 0
+}
+
+// All returns an iterator over the positions and values from front to back
+// the list must not be modified during the iteration
+func (l *List[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for n := l.head; n != nil; n = n.next {
+			for _, v := range n.values {
+				if !yield(i, v) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the positions and values from back to front
+func (l *List[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := l.len - 1
+		for n := l.tail; n != nil; n = n.prev {
+			for j := len(n.values) - 1; j >= 0; j-- {
+				if !yield(i, n.values[j]) {
+					return
+				}
+				i--
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package unrolled
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func values[T any](l *List[T]) []T {
+	res := []T{}
+	for _, v := range l.All() {
+		res = append(res, v)
+	}
+	return res
+}
+
+// checkNodes verifies the links and the occupancy of the nodes
+func checkNodes[T any](t *testing.T, l *List[T]) {
+	count := 0
+	var prev *node[T]
+	for n := l.head; n != nil; n = n.next {
+		assert.Equal(t, prev, n.prev)
+		assert.NotEmpty(t, n.values)
+		assert.LessOrEqual(t, len(n.values), l.capacity())
+		assert.Equal(t, l.capacity(), cap(n.values))
+		if l.head != l.tail {
+			assert.GreaterOrEqual(t, len(n.values), l.capacity()/2)
+		}
+		count += len(n.values)
+		prev = n
+	}
+	assert.Equal(t, prev, l.tail)
+	assert.Equal(t, l.len, count)
+}
+
+func TestList(t *testing.T) {
+	var l List[string]
+	assert.Equal(t, 0, l.Len())
+	_, ok := l.At(0)
+	assert.False(t, ok)
+
+	l.PushBack("b")
+	l.PushFront("a")
+	l.PushBack("d")
+	assert.True(t, l.Insert(2, "c"))
+	assert.False(t, l.Insert(5, "x"))
+	assert.False(t, l.Insert(-1, "x"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values(&l))
+
+	v, ok := l.At(2)
+	assert.True(t, ok)
+	assert.Equal(t, "c", v)
+	assert.True(t, l.Set(2, "C"))
+	assert.False(t, l.Set(4, "x"))
+
+	v, ok = l.Remove(0)
+	assert.True(t, ok)
+	assert.Equal(t, "a", v)
+	_, ok = l.Remove(3)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"b", "C", "d"}, values(&l))
+	checkNodes(t, &l)
+
+	var back []string
+	for i, v := range l.Backward() {
+		assert.Equal(t, l.Len()-1-len(back), i)
+		back = append(back, v)
+	}
+	assert.Equal(t, []string{"d", "C", "b"}, back)
+
+	l.Clear()
+	assert.Equal(t, []string{}, values(&l))
+}
+
+// TestList_Random replays random operations on a List with small nodes and on a slice
+func TestList_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	l := New[int](4)
+	var ref []int
+	for i := 0; i < 20000; i++ {
+		insertBias := 6
+		if (i/2000)%2 == 1 {
+			insertBias = 4
+		}
+		if len(ref) == 0 || rnd.Intn(10) < insertBias {
+			pos := rnd.Intn(len(ref) + 1)
+			assert.True(t, l.Insert(pos, i))
+			ref = slices.Insert(ref, pos, i)
+		} else {
+			pos := rnd.Intn(len(ref))
+			v, ok := l.Remove(pos)
+			assert.True(t, ok)
+			assert.Equal(t, ref[pos], v)
+			ref = slices.Delete(ref, pos, pos+1)
+		}
+		if len(ref) > 0 {
+			pos := rnd.Intn(len(ref))
+			v, _ := l.At(pos)
+			assert.Equal(t, ref[pos], v)
+		}
+		if i%100 == 0 {
+			checkNodes(t, l)
+			assert.Equal(t, append([]int{}, ref...), values(l))
+		}
+	}
+	checkNodes(t, l)
+	assert.Equal(t, append([]int{}, ref...), values(l))
+}
+
+func TestList_Break(t *testing.T) {
+	l := New[int](4)
+	for i := 0; i < 10; i++ {
+		l.PushBack(i)
+	}
+	var got []int
+	for i, v := range l.All() {
+		if i == 6 {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, got)
+}
+
+const benchSize = 10000
+
+func BenchmarkUnrolled_Scan(b *testing.B) {
+	var l List[int]
+	for i := 0; i < benchSize; i++ {
+		l.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for _, v := range l.All() {
+			sum += v
+		}
+	}
+}
+
+func BenchmarkList_Scan(b *testing.B) {
+	l := list.New()
+	for i := 0; i < benchSize; i++ {
+		l.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for e := l.Front(); e != nil; e = e.Next() {
+			sum += e.Value.(int)
+		}
+	}
+}
+
+// the insert benchmarks put values at random positions of a list growing to benchSize,
+// the linked list has to walk to the position, which dominates its cost
+func BenchmarkUnrolled_Insert(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var l List[int]
+		for j := 0; j < benchSize/10; j++ {
+			l.Insert(rnd.Intn(l.Len()+1), j)
+		}
+	}
+}
+
+func BenchmarkList_Insert(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := list.New()
+		for j := 0; j < benchSize/10; j++ {
+			pos := rnd.Intn(l.Len() + 1)
+			if pos == l.Len() {
+				l.PushBack(j)
+				continue
+			}
+			e := l.Front()
+			for ; pos > 0; pos-- {
+				e = e.Next()
+			}
+			l.InsertBefore(j, e)
+		}
+	}
+}
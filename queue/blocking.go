@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by the operations of a BlockingQueue which can no longer succeed because it was closed
+var ErrClosed = errors.New("queue: closed")
+
+// errExpired tells put and take that the caller stopped waiting
+var errExpired = errors.New("queue: expired")
+
+// expired is a closed channel, waiting on it makes an operation non-blocking
+var expired = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// BlockingQueue is a FIFO queue which is safe for concurrent use, like LinkedBlockingQueue in Java
+// producers wait while it is full and consumers wait while it is empty, until a context is done, a timeout expires or it is closed
+// the values are kept in a Deque guarded by a mutex, waiters sleep on channels which are closed to wake them up
+// a BlockingQueue must be created with NewBlockingQueue
+type BlockingQueue[T any] struct {
+	mu     sync.Mutex
+	items  Deque[T]
+	bound  int // 0 for an unbounded queue
+	closed bool
+
+	// created by the first waiter and closed by the next change, nil when nobody waits
+	notEmpty chan struct{}
+	notFull  chan struct{}
+}
+
+// NewBlockingQueue returns a queue which holds at most capacity values, a capacity <= 0 makes it unbounded
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	return &BlockingQueue[T]{bound: max(capacity, 0)}
+}
+
+// waitOn returns the channel which will be closed by the next wake of *ch
+func waitOn(ch *chan struct{}) <-chan struct{} {
+	if *ch == nil {
+		*ch = make(chan struct{})
+	}
+	return *ch
+}
+
+// wake wakes up all goroutines waiting on *ch, they recheck the queue and wait again if they lost the race
+func wake(ch *chan struct{}) {
+	if *ch != nil {
+		close(*ch)
+		*ch = nil
+	}
+}
+
+func (q *BlockingQueue[T]) put(v T, done <-chan struct{}) error {
+	q.mu.Lock()
+	for {
+		if q.closed {
+			q.mu.Unlock()
+			return ErrClosed
+		}
+		if q.bound == 0 || q.items.Len() < q.bound {
+			q.items.PushBack(v)
+			wake(&q.notEmpty)
+			q.mu.Unlock()
+			return nil
+		}
+		ch := waitOn(&q.notFull)
+		q.mu.Unlock()
+		select {
+		case <-ch:
+		case <-done:
+			return errExpired
+		}
+		q.mu.Lock()
+	}
+}
+
+func (q *BlockingQueue[T]) take(done <-chan struct{}) (T, error) {
+	q.mu.Lock()
+	for {
+		if v, ok := q.items.PopFront(); ok {
+			wake(&q.notFull)
+			q.mu.Unlock()
+			return v, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		ch := waitOn(&q.notEmpty)
+		q.mu.Unlock()
+		select {
+		case <-ch:
+		case <-done:
+			var zero T
+			return zero, errExpired
+		}
+		q.mu.Lock()
+	}
+}
+
+// Put adds v at the back, waiting for room while the queue is full
+// it returns ErrClosed if the queue is closed, or the error of ctx if ctx is done first
+func (q *BlockingQueue[T]) Put(ctx context.Context, v T) error {
+	if err := q.put(v, ctx.Done()); err != errExpired {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Take removes and returns the front value, waiting for one while the queue is empty
+// a closed queue still hands out the values it holds, ErrClosed is returned once it is empty
+// it returns the error of ctx if ctx is done first
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	v, err := q.take(ctx.Done())
+	if err != errExpired {
+		return v, err
+	}
+	return v, ctx.Err()
+}
+
+// Offer adds v at the back, waiting up to timeout for room, a timeout <= 0 does not wait at all
+// it returns whether v was added, which is never the case once the queue is closed
+func (q *BlockingQueue[T]) Offer(v T, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return q.put(v, expired) == nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return q.put(v, ctx.Done()) == nil
+}
+
+// Poll removes and returns the front value, waiting up to timeout for one, a timeout <= 0 does not wait at all
+// ok is false when no value arrived in time or the queue is closed and empty
+func (q *BlockingQueue[T]) Poll(timeout time.Duration) (v T, ok bool) {
+	var err error
+	if timeout <= 0 {
+		v, err = q.take(expired)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		v, err = q.take(ctx.Done())
+	}
+	return v, err == nil
+}
+
+// Close stops the queue from accepting values and wakes up all waiters, it is safe to call more than once
+// blocked producers get ErrClosed, consumers keep taking the values left and get ErrClosed after them
+func (q *BlockingQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	wake(&q.notEmpty)
+	wake(&q.notFull)
+}
+
+// Len returns the number of values in the queue, which may be stale as soon as it returns
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// Cap returns the most values the queue holds, 0 for an unbounded queue
+func (q *BlockingQueue[T]) Cap() int {
+	return q.bound
+}
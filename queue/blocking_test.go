@@ -0,0 +1,224 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingQueue(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	assert.Equal(t, 2, q.Cap())
+	ctx := context.Background()
+
+	assert.NoError(t, q.Put(ctx, 1))
+	assert.True(t, q.Offer(2, 0))
+	assert.False(t, q.Offer(3, 0), "a full queue rejects an offer which does not wait")
+	assert.False(t, q.Offer(3, 10*time.Millisecond))
+	assert.Equal(t, 2, q.Len())
+
+	v, err := q.Take(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	v, ok := q.Poll(0)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = q.Poll(0)
+	assert.False(t, ok)
+	_, ok = q.Poll(10 * time.Millisecond)
+	assert.False(t, ok)
+	assert.Zero(t, q.Len())
+}
+
+func TestBlockingQueue_Unbounded(t *testing.T) {
+	q := NewBlockingQueue[int](0)
+	assert.Zero(t, q.Cap())
+	for i := 0; i < 100; i++ {
+		assert.True(t, q.Offer(i, 0))
+	}
+	for i := 0; i < 100; i++ {
+		v, ok := q.Poll(0)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+}
+
+func TestBlockingQueue_Wait(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx := context.Background()
+	q.Offer(1, 0)
+
+	put := make(chan error)
+	go func() {
+		put <- q.Put(ctx, 2)
+	}()
+	select {
+	case <-put:
+		t.Fatal("Put returned while the queue was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	v, _ := q.Take(ctx)
+	assert.Equal(t, 1, v)
+	assert.NoError(t, <-put)
+
+	v, _ = q.Take(ctx)
+	assert.Equal(t, 2, v)
+	took := make(chan int)
+	go func() {
+		v, _ := q.Take(ctx)
+		took <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, q.Offer(3, time.Second))
+	assert.Equal(t, 3, <-took)
+}
+
+func TestBlockingQueue_Context(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	q.Offer(1, 0)
+
+	errs := make(chan error)
+	go func() {
+		errs <- q.Put(ctx, 2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.Equal(t, 1, q.Len())
+
+	q.Poll(0)
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := q.Take(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBlockingQueue_Close(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx := context.Background()
+	q.Offer(1, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.ErrorIs(t, q.Put(ctx, 2), ErrClosed)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	q.Close()
+	wg.Wait()
+	assert.False(t, q.Offer(2, 0))
+
+	// the values left are still handed out
+	v, err := q.Take(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+	_, err = q.Take(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+	_, ok := q.Poll(time.Second)
+	assert.False(t, ok)
+
+	// blocked consumers are woken up too
+	q = NewBlockingQueue[int](1)
+	errs := make(chan error)
+	go func() {
+		_, err := q.Take(ctx)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	assert.ErrorIs(t, <-errs, ErrClosed)
+}
+
+func TestBlockingQueue_Stress(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 5000
+	q := NewBlockingQueue[int](8)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				assert.NoError(t, q.Put(ctx, p*perProducer+i))
+			}
+		}(p)
+	}
+
+	results := make(chan []int)
+	for c := 0; c < consumers; c++ {
+		go func() {
+			var got []int
+			for {
+				v, err := q.Take(ctx)
+				if err != nil {
+					results <- got
+					return
+				}
+				got = append(got, v)
+			}
+		}()
+	}
+	wg.Wait()
+	q.Close()
+
+	seen := make([]bool, producers*perProducer)
+	last := make(map[int]int) // per producer, to check each consumer sees its values in order
+	for c := 0; c < consumers; c++ {
+		clear(last)
+		for _, v := range <-results {
+			assert.False(t, seen[v], "value %d taken twice", v)
+			seen[v] = true
+			p := v / perProducer
+			if prev, ok := last[p]; ok {
+				assert.Less(t, prev, v)
+			}
+			last[p] = v
+		}
+	}
+	for v, ok := range seen {
+		assert.True(t, ok, "value %d lost", v)
+	}
+}
+
+func benchmarkPipe(b *testing.B, put func(int), take func() int) {
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < b.N; i++ {
+			take()
+		}
+		close(done)
+	}()
+	for i := 0; i < b.N; i++ {
+		put(i)
+	}
+	<-done
+}
+
+func BenchmarkBlockingQueue(b *testing.B) {
+	q := NewBlockingQueue[int](64)
+	ctx := context.Background()
+	benchmarkPipe(b, func(v int) {
+		q.Put(ctx, v)
+	}, func() int {
+		v, _ := q.Take(ctx)
+		return v
+	})
+}
+
+func BenchmarkChannel(b *testing.B) {
+	c := make(chan int, 64)
+	benchmarkPipe(b, func(v int) {
+		c <- v
+	}, func() int {
+		return <-c
+	})
+}
@@ -0,0 +1,211 @@
+package queue
+
+import "iter"
+
+// FullPolicy decides what a bounded Deque does with a push when it is full
+type FullPolicy int
+
+const (
+	// Reject refuses the new value, the push returns false
+	Reject FullPolicy = iota
+	// Overwrite drops the value at the other end to make room, like a ring buffer
+	Overwrite
+)
+
+// minDequeCap is the smallest buffer of an unbounded Deque, it does not shrink below it
+const minDequeCap = 8
+
+// Deque is a double-ended queue stored in a ring buffer
+// pushing and popping at both ends take amortized O(1) time, and the values are contiguous in memory
+// an unbounded Deque doubles its buffer when full and halves it when only a quarter is used
+// the zero value is an empty unbounded Deque ready to use
+type Deque[T any] struct {
+	buf    []T
+	head   int // index of the front value in buf
+	len    int
+	minCap int // the capacity given to NewDeque, an unbounded Deque does not shrink below it
+	bound  int // 0 for an unbounded Deque
+	policy FullPolicy
+}
+
+// NewDeque returns an unbounded Deque with room for capacity values before it grows
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < minDequeCap {
+		capacity = minDequeCap
+	}
+	return &Deque[T]{buf: make([]T, capacity), minCap: capacity}
+}
+
+// NewBoundedDeque returns a Deque which never holds more than bound values, policy decides what happens when it is full
+func NewBoundedDeque[T any](bound int, policy FullPolicy) *Deque[T] {
+	if bound <= 0 {
+		panic("queue: bound of a Deque must be positive")
+	}
+	return &Deque[T]{
+		buf:    make([]T, bound),
+		bound:  bound,
+		policy: policy,
+	}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+// Cap returns the number of values the Deque holds without growing, which is the bound of a bounded Deque
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+// IsFull returns whether a push would be rejected or overwrite a value, it is always false for an unbounded Deque
+func (d *Deque[T]) IsFull() bool {
+	return d.bound > 0 && d.len == d.bound
+}
+
+// index maps the i-th value to its slot in buf
+func (d *Deque[T]) index(i int) int {
+	i += d.head
+	if i >= len(d.buf) {
+		i -= len(d.buf)
+	}
+	return i
+}
+
+func (d *Deque[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+	if d.head+d.len <= len(d.buf) {
+		copy(buf, d.buf[d.head:d.head+d.len])
+	} else {
+		n := copy(buf, d.buf[d.head:])
+		copy(buf[n:], d.buf[:d.len-n])
+	}
+	d.buf = buf
+	d.head = 0
+}
+
+// makeRoom prepares a push, it returns false when the push must be rejected
+// front tells which end is pushed, an overwriting Deque drops the value at the other end
+func (d *Deque[T]) makeRoom(front bool) bool {
+	if d.bound == 0 {
+		if d.len == len(d.buf) {
+			d.resize(max(2*len(d.buf), minDequeCap))
+		}
+		return true
+	}
+	if d.len < d.bound {
+		return true
+	}
+	if d.policy == Reject {
+		return false
+	}
+	if front {
+		d.PopBack()
+	} else {
+		d.PopFront()
+	}
+	return true
+}
+
+// shrink halves the buffer of an unbounded Deque once it is only a quarter full, down to the capacity it started with
+func (d *Deque[T]) shrink() {
+	floor := max(d.minCap, minDequeCap)
+	if d.bound == 0 && len(d.buf) > floor && d.len <= len(d.buf)/4 {
+		d.resize(max(len(d.buf)/2, floor))
+	}
+}
+
+// PushBack adds v at the back, it returns false if a full Deque rejects it
+func (d *Deque[T]) PushBack(v T) bool {
+	if !d.makeRoom(false) {
+		return false
+	}
+	d.buf[d.index(d.len)] = v
+	d.len++
+	return true
+}
+
+// PushFront adds v at the front, it returns false if a full Deque rejects it
+func (d *Deque[T]) PushFront(v T) bool {
+	if !d.makeRoom(true) {
+		return false
+	}
+	d.head--
+	if d.head < 0 {
+		d.head += len(d.buf)
+	}
+	d.buf[d.head] = v
+	d.len++
+	return true
+}
+
+// PopFront removes and returns the front value, ok is false when the Deque is empty
+func (d *Deque[T]) PopFront() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	var zero T
+	v, d.buf[d.head] = d.buf[d.head], zero
+	d.head = d.index(1)
+	d.len--
+	d.shrink()
+	return v, true
+}
+
+// PopBack removes and returns the back value, ok is false when the Deque is empty
+func (d *Deque[T]) PopBack() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	var zero T
+	i := d.index(d.len - 1)
+	v, d.buf[i] = d.buf[i], zero
+	d.len--
+	d.shrink()
+	return v, true
+}
+
+// Front returns the front value without removing it, ok is false when the Deque is empty
+func (d *Deque[T]) Front() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	return d.buf[d.head], true
+}
+
+// Back returns the back value without removing it, ok is false when the Deque is empty
+func (d *Deque[T]) Back() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	return d.buf[d.index(d.len-1)], true
+}
+
+// At returns the i-th value counting from the front, ok is false when i is out of [0, Len())
+func (d *Deque[T]) At(i int) (v T, ok bool) {
+	if i < 0 || i >= d.len {
+		return v, false
+	}
+	return d.buf[d.index(i)], true
+}
+
+// Clear removes all values, an unbounded Deque also gives its buffer back
+func (d *Deque[T]) Clear() {
+	if d.bound == 0 {
+		d.buf = nil
+	} else {
+		clear(d.buf)
+	}
+	d.head = 0
+	d.len = 0
+}
+
+// All returns an iterator over the values from front to back, the Deque must not be modified during the iteration
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.len; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package queue
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func dequeValues[T any](d *Deque[T]) []T {
+	res := []T{}
+	for v := range d.All() {
+		res = append(res, v)
+	}
+	return res
+}
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	_, ok := d.PopFront()
+	assert.False(t, ok)
+	_, ok = d.Back()
+	assert.False(t, ok)
+
+	assert.True(t, d.PushBack(2))
+	assert.True(t, d.PushFront(1))
+	assert.True(t, d.PushBack(3))
+	assert.Equal(t, []int{1, 2, 3}, dequeValues(&d))
+	assert.Equal(t, 3, d.Len())
+	assert.False(t, d.IsFull())
+
+	v, ok := d.At(1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	_, ok = d.At(3)
+	assert.False(t, ok)
+	_, ok = d.At(-1)
+	assert.False(t, ok)
+
+	v, _ = d.Front()
+	assert.Equal(t, 1, v)
+	v, _ = d.Back()
+	assert.Equal(t, 3, v)
+	v, _ = d.PopBack()
+	assert.Equal(t, 3, v)
+	v, _ = d.PopFront()
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []int{2}, dequeValues(&d))
+
+	d.Clear()
+	assert.Zero(t, d.Len())
+	assert.True(t, d.PushFront(7))
+	assert.Equal(t, []int{7}, dequeValues(&d))
+}
+
+// TestDeque_Random replays random operations on a Deque and on a slice
+func TestDeque_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	d := NewDeque[int](0)
+	var ref []int
+	for i := 0; i < 100000; i++ {
+		// grow for a while, then mostly shrink, so both resizes happen many times
+		pushBias := 6
+		if (i/5000)%2 == 1 {
+			pushBias = 3
+		}
+		switch op := rnd.Intn(10); {
+		case op < pushBias && op%2 == 0:
+			d.PushBack(i)
+			ref = append(ref, i)
+		case op < pushBias:
+			d.PushFront(i)
+			ref = append([]int{i}, ref...)
+		case op%2 == 0:
+			v, ok := d.PopBack()
+			assert.Equal(t, len(ref) > 0, ok)
+			if ok {
+				assert.Equal(t, ref[len(ref)-1], v)
+				ref = ref[:len(ref)-1]
+			}
+		default:
+			v, ok := d.PopFront()
+			assert.Equal(t, len(ref) > 0, ok)
+			if ok {
+				assert.Equal(t, ref[0], v)
+				ref = ref[1:]
+			}
+		}
+		if len(ref) > 0 {
+			j := rnd.Intn(len(ref))
+			v, ok := d.At(j)
+			assert.True(t, ok)
+			assert.Equal(t, ref[j], v)
+		}
+		assert.Equal(t, len(ref), d.Len())
+		assert.GreaterOrEqual(t, d.Cap(), d.Len())
+		if d.Cap() > minDequeCap {
+			assert.Greater(t, d.Len(), d.Cap()/4-1, "the buffer should have shrunk")
+		}
+	}
+	assert.Equal(t, ref, dequeValues(d))
+}
+
+func TestDeque_Shrink(t *testing.T) {
+	d := NewDeque[int](0)
+	for i := 0; i < 1000; i++ {
+		d.PushBack(i)
+	}
+	assert.Equal(t, 1024, d.Cap())
+	for i := 0; i < 990; i++ {
+		d.PopFront()
+	}
+	assert.Equal(t, 32, d.Cap())
+	assert.Equal(t, []int{990, 991, 992, 993, 994, 995, 996, 997, 998, 999}, dequeValues(d))
+}
+
+func TestDeque_ShrinkToInitialCap(t *testing.T) {
+	d := NewDeque[int](10)
+	d.PushBack(1)
+	d.PopFront()
+	assert.Equal(t, 10, d.Cap())
+
+	for i := 0; i < 100; i++ {
+		d.PushBack(i)
+	}
+	assert.Equal(t, 160, d.Cap())
+	for i := 0; i < 100; i++ {
+		d.PopBack()
+	}
+	assert.Equal(t, 10, d.Cap())
+
+	var zero Deque[int]
+	for i := 0; i < 100; i++ {
+		zero.PushBack(i)
+	}
+	for i := 0; i < 100; i++ {
+		zero.PopFront()
+	}
+	assert.Equal(t, minDequeCap, zero.Cap())
+}
+
+func TestDeque_BoundedReject(t *testing.T) {
+	d := NewBoundedDeque[int](3, Reject)
+	assert.True(t, d.PushBack(1))
+	assert.True(t, d.PushBack(2))
+	assert.True(t, d.PushFront(0))
+	assert.True(t, d.IsFull())
+	assert.False(t, d.PushBack(3))
+	assert.False(t, d.PushFront(-1))
+	assert.Equal(t, []int{0, 1, 2}, dequeValues(d))
+	assert.Equal(t, 3, d.Cap())
+
+	d.PopFront()
+	d.PopFront()
+	assert.Equal(t, 3, d.Cap(), "a bounded deque does not shrink")
+	assert.True(t, d.PushBack(3))
+	assert.Equal(t, []int{2, 3}, dequeValues(d))
+}
+
+func TestDeque_BoundedOverwrite(t *testing.T) {
+	d := NewBoundedDeque[int](3, Overwrite)
+	for i := 0; i < 5; i++ {
+		assert.True(t, d.PushBack(i))
+	}
+	assert.Equal(t, []int{2, 3, 4}, dequeValues(d))
+	assert.True(t, d.PushFront(1))
+	assert.Equal(t, []int{1, 2, 3}, dequeValues(d))
+
+	d.Clear()
+	assert.Equal(t, 3, d.Cap())
+	assert.True(t, d.PushFront(9))
+	assert.Equal(t, []int{9}, dequeValues(d))
+
+	assert.Panics(t, func() {
+		NewBoundedDeque[int](0, Reject)
+	})
+}
+
+func TestDeque_ReleasesValues(t *testing.T) {
+	d := NewDeque[*int](0)
+	x := 1
+	d.PushBack(&x)
+	d.PushBack(&x)
+	d.PopFront()
+	d.PopBack()
+	assert.True(t, slices.Equal(make([]*int, d.Cap()), d.buf), "popped slots must be cleared")
+}
+
+func BenchmarkDeque_PushPop(b *testing.B) {
+	b.ReportAllocs()
+	var d Deque[int]
+	for i := 0; i < b.N; i++ {
+		d.PushBack(i)
+		if i%3 == 2 {
+			d.PopFront()
+		}
+	}
+}
+
+func BenchmarkList_PushPop(b *testing.B) {
+	b.ReportAllocs()
+	var l list.List
+	for i := 0; i < b.N; i++ {
+		l.PushBack(i)
+		if i%3 == 2 {
+			l.Remove(l.Front())
+		}
+	}
+}
+
+func BenchmarkDeque_Scan(b *testing.B) {
+	var d Deque[int]
+	for i := 0; i < 10000; i++ {
+		d.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for v := range d.All() {
+			sum += v
+		}
+	}
+}
+
+func BenchmarkList_Scan(b *testing.B) {
+	var l list.List
+	for i := 0; i < 10000; i++ {
+		l.PushBack(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for e := l.Front(); e != nil; e = e.Next() {
+			sum += e.Value.(int)
+		}
+	}
+}
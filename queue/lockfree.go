@@ -0,0 +1,87 @@
+// Queues for producer-consumer pipelines
+package queue
+
+import "sync/atomic"
+
+type lfNode[T any] struct {
+	value T
+	next  atomic.Pointer[lfNode[T]]
+}
+
+// LockFreeQueue is an unbounded FIFO queue which is safe for concurrent use without locks
+// it is the algorithm of Michael and Scott: head points to a dummy node, whose successor holds the front value,
+// and tail points to the last node or lags one behind it, in which case any thread may swing it forward
+// the garbage collector keeps removed nodes alive while they are referenced, so the algorithm is free of ABA
+// a LockFreeQueue must be created with NewLockFreeQueue
+type LockFreeQueue[T any] struct {
+	head atomic.Pointer[lfNode[T]]
+	tail atomic.Pointer[lfNode[T]]
+	len  atomic.Int64
+}
+
+func NewLockFreeQueue[T any]() *LockFreeQueue[T] {
+	q := &LockFreeQueue[T]{}
+	dummy := &lfNode[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// Enqueue adds v at the back of the queue
+func (q *LockFreeQueue[T]) Enqueue(v T) {
+	n := &lfNode[T]{value: v}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// tail is lagging, help the other enqueuer before retrying
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, n) {
+			// failing is fine, then someone else has already moved tail past n
+			q.tail.CompareAndSwap(tail, n)
+			break
+		}
+	}
+	q.len.Add(1)
+}
+
+// TryDequeue removes and returns the front value, ok is false when the queue is empty
+// the removed node becomes the new dummy, so its value stays reachable until the next dequeue
+func (q *LockFreeQueue[T]) TryDequeue() (v T, ok bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if next == nil {
+			return v, false
+		}
+		if head == tail {
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		value := next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.len.Add(-1)
+			return value, true
+		}
+	}
+}
+
+// Len returns the number of values in the queue
+// it is exact when no other goroutine is using the queue, otherwise it is a snapshot which may already be stale
+func (q *LockFreeQueue[T]) Len() int {
+	n := q.len.Load()
+	if n < 0 {
+		// a dequeue may count itself before the enqueue it observed does
+		return 0
+	}
+	return int(n)
+}
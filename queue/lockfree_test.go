@@ -0,0 +1,151 @@
+package queue
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/derekcdz/dsgym/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeQueue(t *testing.T) {
+	q := NewLockFreeQueue[int]()
+	_, ok := q.TryDequeue()
+	assert.False(t, ok)
+	assert.Zero(t, q.Len())
+
+	for i := 0; i < 10; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 10, q.Len())
+	for i := 0; i < 5; i++ {
+		v, ok := q.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	q.Enqueue(10)
+	for i := 5; i <= 10; i++ {
+		v, ok := q.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+	_, ok = q.TryDequeue()
+	assert.False(t, ok)
+	assert.Zero(t, q.Len())
+}
+
+type item struct {
+	producer, seq int
+}
+
+// TestLockFreeQueue_Stress checks that every value is dequeued exactly once,
+// and that each consumer sees the values of a producer in the order they were enqueued
+func TestLockFreeQueue_Stress(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 5000
+	q := NewLockFreeQueue[item]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Enqueue(item{p, i})
+			}
+		}(p)
+	}
+
+	received := make([][]item, consumers)
+	var done sync.WaitGroup
+	var remaining sync.WaitGroup
+	remaining.Add(producers * perProducer)
+	stop := make(chan struct{})
+	for c := 0; c < consumers; c++ {
+		done.Add(1)
+		go func(c int) {
+			defer done.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if v, ok := q.TryDequeue(); ok {
+					received[c] = append(received[c], v)
+					remaining.Done()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	remaining.Wait()
+	close(stop)
+	done.Wait()
+
+	seen := make(map[item]bool)
+	for _, got := range received {
+		last := make(map[int]int)
+		for _, v := range got {
+			assert.False(t, seen[v], "%v dequeued twice", v)
+			seen[v] = true
+			if prev, ok := last[v.producer]; ok {
+				assert.Less(t, prev, v.seq, "values of producer %d out of order", v.producer)
+			}
+			last[v.producer] = v.seq
+		}
+	}
+	assert.Len(t, seen, producers*perProducer)
+	assert.Zero(t, q.Len())
+	_, ok := q.TryDequeue()
+	assert.False(t, ok)
+}
+
+// mutexList is the baseline of the benchmarks, a list.List guarded by a mutex
+type mutexList struct {
+	mu sync.Mutex
+	l  list.List
+}
+
+func (m *mutexList) Enqueue(v int) {
+	m.mu.Lock()
+	m.l.PushBack(v)
+	m.mu.Unlock()
+}
+
+func (m *mutexList) TryDequeue() (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	front := m.l.Front()
+	if front == nil {
+		return 0, false
+	}
+	return m.l.Remove(front).(int), true
+}
+
+type intQueue interface {
+	Enqueue(v int)
+	TryDequeue() (int, bool)
+}
+
+func benchmarkQueue(b *testing.B, q intQueue) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%2 == 0 {
+				q.Enqueue(i)
+			} else {
+				q.TryDequeue()
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeQueue(b *testing.B) {
+	benchmarkQueue(b, NewLockFreeQueue[int]())
+}
+
+func BenchmarkMutexList(b *testing.B) {
+	benchmarkQueue(b, &mutexList{})
+}
@@ -0,0 +1,289 @@
+package skip_list
+
+import (
+	"iter"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+type cNode struct {
+	key   Key
+	value atomic.Pointer[Value]
+	next  []atomic.Pointer[cNode]
+
+	mu          sync.Mutex  // guards the links out of the node while writers change them
+	marked      atomic.Bool // set when the node is being deleted, it is then unlinked level by level
+	fullyLinked atomic.Bool // set once the node is linked on all its levels
+}
+
+// live reports whether n holds a key of the map, a node is in the map from fully linked until marked
+func (n *cNode) live() bool {
+	return n.fullyLinked.Load() && !n.marked.Load()
+}
+
+// ConcurrentSkipList is a sorted map which is safe for concurrent use, like ConcurrentSkipListMap in Java
+// it is the lazy skip list of Herlihy and Shavit: readers never lock, and writers lock only the predecessors
+// of the node they link or unlink, so writers on distant keys do not contend
+// iteration is weakly consistent: it never yields a key twice, reflects the changes made before it starts,
+// and may or may not reflect the changes made while it runs
+// a ConcurrentSkipList must be created with NewConcurrent
+type ConcurrentSkipList struct {
+	head *cNode // a sentinel linked on all levels, its key is never compared
+	size atomic.Int64
+}
+
+func NewConcurrent() *ConcurrentSkipList {
+	return &ConcurrentSkipList{
+		head: &cNode{next: make([]atomic.Pointer[cNode], MaxLevel)},
+	}
+}
+
+// find fills preds and succs with the neighbours of k on each level, preds[i] < k <= succs[i]
+// it returns the highest level on which succs holds k, or -1 if k was not found
+func (l *ConcurrentSkipList) find(k Key, preds, succs *[MaxLevel]*cNode) int {
+	found := -1
+	pred := l.head
+	for i := MaxLevel - 1; i >= 0; i-- {
+		curr := pred.next[i].Load()
+		for curr != nil && curr.key.CompareTo(k) < 0 {
+			pred = curr
+			curr = pred.next[i].Load()
+		}
+		if found == -1 && curr != nil && curr.key.CompareTo(k) == 0 {
+			found = i
+		}
+		preds[i] = pred
+		succs[i] = curr
+	}
+	return found
+}
+
+// lockPreds locks the distinct preds of the levels below top and checks that each is live and still links to succs
+// a pred may appear on consecutive levels, it is locked once
+// the succs must not be marked either, unless they are the node being deleted
+// it returns the unlock function, which must be called whether or not the check passed
+func lockPreds(top int, preds, succs *[MaxLevel]*cNode, deleting bool) (valid bool, unlock func()) {
+	var prev *cNode
+	locked := 0
+	unlock = func() {
+		var prev *cNode
+		for i := 0; i < locked; i++ {
+			if preds[i] != prev {
+				preds[i].mu.Unlock()
+				prev = preds[i]
+			}
+		}
+	}
+	for i := 0; i < top; i++ {
+		pred, succ := preds[i], succs[i]
+		if pred != prev {
+			pred.mu.Lock()
+			prev = pred
+		}
+		locked = i + 1
+		if pred.marked.Load() || pred.next[i].Load() != succ || !deleting && succ != nil && succ.marked.Load() {
+			return false, unlock
+		}
+	}
+	return true, unlock
+}
+
+// Get returns the Value associated with k, ok is false when k is not in the map or k is nil
+func (l *ConcurrentSkipList) Get(k Key) (v Value, ok bool) {
+	if k == nil {
+		return nil, false
+	}
+	var preds, succs [MaxLevel]*cNode
+	found := l.find(k, &preds, &succs)
+	if found == -1 || !succs[found].live() {
+		return nil, false
+	}
+	return *succs[found].value.Load(), true
+}
+
+// Put stores v associated with k, replacing the previous Value of k
+// When k is nil, the method will directly return and no values will be stored
+func (l *ConcurrentSkipList) Put(k Key, v Value) {
+	if k == nil {
+		return
+	}
+	top := randomLevel(rand.Int63)
+	var preds, succs [MaxLevel]*cNode
+	for {
+		if found := l.find(k, &preds, &succs); found != -1 {
+			n := succs[found]
+			if !n.marked.Load() {
+				// another Put is linking n, the value may only be replaced once n is in the map
+				for !n.fullyLinked.Load() {
+					runtime.Gosched()
+				}
+				n.value.Store(&v)
+				return
+			}
+			// n is being deleted, retry once it is unlinked
+			continue
+		}
+
+		valid, unlock := lockPreds(top, &preds, &succs, false)
+		if !valid {
+			unlock()
+			continue
+		}
+		n := &cNode{key: k, next: make([]atomic.Pointer[cNode], top)}
+		n.value.Store(&v)
+		for i := 0; i < top; i++ {
+			n.next[i].Store(succs[i])
+		}
+		for i := 0; i < top; i++ {
+			preds[i].next[i].Store(n)
+		}
+		n.fullyLinked.Store(true)
+		unlock()
+		l.size.Add(1)
+		return
+	}
+}
+
+// Delete deletes k from the map, it returns whether k was in the map
+func (l *ConcurrentSkipList) Delete(k Key) bool {
+	if k == nil {
+		return false
+	}
+	var preds, succs [MaxLevel]*cNode
+	var victim *cNode
+	for {
+		found := l.find(k, &preds, &succs)
+		if victim == nil {
+			// only a fully linked node found on its top level is complete, others are still being linked or unlinked
+			if found == -1 {
+				return false
+			}
+			n := succs[found]
+			if !n.live() || len(n.next)-1 != found {
+				return false
+			}
+			n.mu.Lock()
+			if n.marked.Load() {
+				n.mu.Unlock()
+				return false
+			}
+			// from here on n is out of the map, we keep it locked until it is unlinked
+			n.marked.Store(true)
+			victim = n
+		}
+
+		// victim cannot be unlinked by anyone else, so succs holds it on all its levels
+		top := len(victim.next)
+		valid, unlock := lockPreds(top, &preds, &succs, true)
+		if !valid {
+			unlock()
+			continue
+		}
+		for i := top - 1; i >= 0; i-- {
+			preds[i].next[i].Store(victim.next[i].Load())
+		}
+		victim.mu.Unlock()
+		unlock()
+		l.size.Add(-1)
+		return true
+	}
+}
+
+// Contains returns whether k is in the map
+func (l *ConcurrentSkipList) Contains(k Key) bool {
+	_, ok := l.Get(k)
+	return ok
+}
+
+// Len returns the number of keys in the map, which may be stale as soon as it returns
+func (l *ConcurrentSkipList) Len() int {
+	return int(l.size.Load())
+}
+
+// lower returns the last live node whose key is less than k, or less than or equal to k when inclusive is set
+// a nil k stands for a key greater than all, nil is returned if there is no such node
+func (l *ConcurrentSkipList) lower(k Key, inclusive bool) *cNode {
+	for {
+		pred := l.head
+		for i := MaxLevel - 1; i >= 0; i-- {
+			for curr := pred.next[i].Load(); curr != nil; curr = pred.next[i].Load() {
+				if k != nil {
+					cmp := curr.key.CompareTo(k)
+					if cmp > 0 || cmp == 0 && !inclusive {
+						break
+					}
+				}
+				pred = curr
+			}
+		}
+		if pred == l.head {
+			return nil
+		}
+		if pred.live() {
+			return pred
+		}
+		// pred is being linked or unlinked, look before it
+		k, inclusive = pred.key, false
+	}
+}
+
+// ceiling returns the first live node whose key is greater than or equal to k, or nil
+func (l *ConcurrentSkipList) ceiling(k Key) *cNode {
+	var preds, succs [MaxLevel]*cNode
+	l.find(k, &preds, &succs)
+	// marked nodes keep their links, so the bottom level can be walked past them
+	n := succs[0]
+	for n != nil && !n.live() {
+		n = n.next[0].Load()
+	}
+	return n
+}
+
+// Floor returns the maximum key which is less than or equals k and its Value, nil and nil if there is none
+func (l *ConcurrentSkipList) Floor(k Key) (Key, Value) {
+	if k == nil {
+		return nil, nil
+	}
+	n := l.lower(k, true)
+	if n == nil {
+		return nil, nil
+	}
+	return n.key, *n.value.Load()
+}
+
+// Ceiling returns the minimum key which is greater than or equals k and its Value, nil and nil if there is none
+func (l *ConcurrentSkipList) Ceiling(k Key) (Key, Value) {
+	if k == nil {
+		return nil, nil
+	}
+	n := l.ceiling(k)
+	if n == nil {
+		return nil, nil
+	}
+	return n.key, *n.value.Load()
+}
+
+// All returns a weakly consistent iterator over the key-value pairs in ascending order of keys
+func (l *ConcurrentSkipList) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for n := l.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			if n.live() && !yield(n.key, *n.value.Load()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns a weakly consistent iterator over the key-value pairs in descending order of keys
+// there are no backward links, each step searches for the key before the last one in O(logN) expected
+func (l *ConcurrentSkipList) Backward() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for n := l.lower(nil, false); n != nil; n = l.lower(n.key, false) {
+			if !yield(n.key, *n.value.Load()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package skip_list
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentSkipList(t *testing.T) {
+	l := NewConcurrent()
+	_, ok := l.Get(intKey(1))
+	assert.False(t, ok)
+	assert.False(t, l.Delete(intKey(1)))
+	k, _ := l.Floor(intKey(1))
+	assert.Nil(t, k)
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		l.Put(intKey(k), k*10)
+	}
+	l.Put(intKey(3), "three")
+	l.Put(nil, 0)
+	assert.Equal(t, 5, l.Len())
+	v, ok := l.Get(intKey(3))
+	assert.True(t, ok)
+	assert.Equal(t, "three", v)
+	assert.True(t, l.Contains(intKey(9)))
+	assert.False(t, l.Contains(intKey(4)))
+
+	k, v = l.Floor(intKey(6))
+	assert.Equal(t, intKey(5), k)
+	assert.Equal(t, 50, v)
+	k, _ = l.Floor(intKey(7))
+	assert.Equal(t, intKey(7), k)
+	k, _ = l.Floor(intKey(0))
+	assert.Nil(t, k)
+	k, v = l.Ceiling(intKey(6))
+	assert.Equal(t, intKey(7), k)
+	assert.Equal(t, 70, v)
+	k, _ = l.Ceiling(intKey(10))
+	assert.Nil(t, k)
+
+	var keys []Key
+	for k := range l.All() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, keysOf(1, 3, 5, 7, 9), keys)
+	keys = keys[:0]
+	for k := range l.Backward() {
+		keys = append(keys, k)
+		if k == intKey(5) {
+			break
+		}
+	}
+	assert.Equal(t, keysOf(9, 7, 5), keys)
+
+	assert.True(t, l.Delete(intKey(5)))
+	assert.False(t, l.Delete(intKey(5)))
+	assert.Equal(t, 4, l.Len())
+	k, _ = l.Floor(intKey(6))
+	assert.Equal(t, intKey(3), k)
+}
+
+// every writer owns the keys congruent to its index, so the expected map is known at the end,
+// while readers check that the iterations stay sorted
+func TestConcurrentSkipList_Stress(t *testing.T) {
+	const writers, readers, keys, ops = 4, 2, 1000, 5000
+	l := NewConcurrent()
+	expected := make([]map[int]int, writers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		expected[w] = map[int]int{}
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < ops; i++ {
+				k := r.Intn(keys/writers)*writers + w
+				if r.Intn(3) == 0 {
+					_, had := expected[w][k]
+					assert.Equal(t, had, l.Delete(intKey(k)))
+					delete(expected[w], k)
+				} else {
+					l.Put(intKey(k), i)
+					expected[w][k] = i
+				}
+			}
+		}(w)
+	}
+
+	stop := make(chan struct{})
+	var rg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		rg.Add(1)
+		go func() {
+			defer rg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				prev := -1
+				for k := range l.All() {
+					assert.Less(t, prev, int(k.(intKey)))
+					prev = int(k.(intKey))
+				}
+				prev = keys
+				for k := range l.Backward() {
+					assert.Greater(t, prev, int(k.(intKey)))
+					prev = int(k.(intKey))
+				}
+				if k, _ := l.Floor(intKey(keys / 2)); k != nil {
+					assert.LessOrEqual(t, int(k.(intKey)), keys/2)
+				}
+				if k, _ := l.Ceiling(intKey(keys / 2)); k != nil {
+					assert.GreaterOrEqual(t, int(k.(intKey)), keys/2)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	rg.Wait()
+
+	size := 0
+	for w := 0; w < writers; w++ {
+		size += len(expected[w])
+		for k, want := range expected[w] {
+			v, ok := l.Get(intKey(k))
+			assert.True(t, ok)
+			assert.Equal(t, want, v)
+		}
+	}
+	assert.Equal(t, size, l.Len())
+	n := 0
+	for k := range l.All() {
+		_, ok := expected[int(k.(intKey))%writers][int(k.(intKey))]
+		assert.True(t, ok, "key %v should have been deleted", k)
+		n++
+	}
+	assert.Equal(t, size, n)
+}
+
+// all goroutines fight over a few keys, the size must agree with the keys left in the end
+func TestConcurrentSkipList_Contention(t *testing.T) {
+	const goroutines, keys, ops = 8, 4, 2000
+	l := NewConcurrent()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < ops; i++ {
+				k := intKey(r.Intn(keys))
+				if r.Intn(2) == 0 {
+					l.Delete(k)
+				} else {
+					l.Put(k, g)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	n := 0
+	for range l.All() {
+		n++
+	}
+	assert.Equal(t, n, l.Len())
+}
+
+func BenchmarkConcurrentSkipList_Put(b *testing.B) {
+	l := NewConcurrent()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			k := r.Intn(1 << 20)
+			l.Put(intKey(k), k)
+		}
+	})
+}
+
+func BenchmarkConcurrentSkipList_Get(b *testing.B) {
+	l := NewConcurrent()
+	for i := 0; i < 1<<16; i++ {
+		l.Put(intKey(i), i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			l.Get(intKey(r.Intn(1 << 16)))
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package skip_list
+
+import "iter"
+
+// All returns an iterator over the key-value pairs in ascending order of keys
+// the map must not be modified during the iteration
+func (l *SkipList) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if l.size == 0 {
+			return
+		}
+		for x := l.head.levels[0].next; x != nil; x = x.levels[0].next {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the key-value pairs in descending order of keys
+func (l *SkipList) Backward() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for x := l.tail; x != nil; x = x.prev {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the key-value pairs whose keys are in [lb, ub], in ascending order
+// like KeysBetween, nothing is yielded if lb or ub is nil
+func (l *SkipList) Range(lb, ub Key) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if lb == nil || ub == nil || lb.CompareTo(ub) > 0 || l.head.levels == nil {
+			return
+		}
+		var update [MaxLevel]*node
+		var rank [MaxLevel]int
+		l.findLess(lb, &update, &rank)
+		for x := update[0].levels[0].next; x != nil && x.key.CompareTo(ub) <= 0; x = x.levels[0].next {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
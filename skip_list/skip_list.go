@@ -0,0 +1,361 @@
+// A map implemented with skip list, an alternative to rb_tree.RBTree which needs no rebalancing
+// every node is linked on a random number of levels, so searching, inserting and deleting are all O(logN) expected
+// each link also counts the nodes it skips, which gives Select, Rank and SizeBetween in O(logN) expected
+package skip_list
+
+import (
+	"math/rand"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+)
+
+type (
+	Key   = rb_tree.Key
+	Value = rb_tree.Value
+)
+
+const (
+	// MaxLevel bounds the number of levels, enough for 4^32 keys
+	MaxLevel = 32
+	// a node linked on level i is also linked on level i+1 with probability 1/p
+	p = 4
+)
+
+type link struct {
+	next *node
+	span int // number of bottom level steps to next, or to the end of the list when next is nil
+}
+
+type node struct {
+	key    Key
+	value  Value
+	prev   *node // on the bottom level, nil for the first node
+	levels []link
+}
+
+// SkipList implements rb_tree.SortedMap
+// the zero value is an empty map drawing its levels from the global source of math/rand,
+// it is only set up by the first method which modifies it, so reading it concurrently is safe
+type SkipList struct {
+	head  node // a sentinel linked on all levels, its key is never compared
+	tail  *node
+	level int // number of levels in use
+	size  int
+	rnd   *rand.Rand
+}
+
+// New returns an empty map drawing its levels from src, a nil src means the global source of math/rand
+// a fixed src makes the shape of the list, and so its performance, reproducible
+func New(src rand.Source) *SkipList {
+	l := &SkipList{}
+	if src != nil {
+		l.rnd = rand.New(src)
+	}
+	return l
+}
+
+func (l *SkipList) checkAndInit() {
+	if l.head.levels == nil {
+		l.head.levels = make([]link, MaxLevel)
+		l.level = 1
+	}
+}
+
+func (l *SkipList) randomLevel() int {
+	if l.rnd != nil {
+		return randomLevel(l.rnd.Int63)
+	}
+	return randomLevel(rand.Int63)
+}
+
+// randomLevel draws a level in [1, MaxLevel], level i+1 being p times less likely than level i
+func randomLevel(int63 func() int64) int {
+	lvl := 1
+	for lvl < MaxLevel && int63()%p == 0 {
+		lvl++
+	}
+	return lvl
+}
+
+// findLess fills update with the last node on each level whose key is less than k, and rank with its position
+// the head has position 0 and the first node position 1
+func (l *SkipList) findLess(k Key, update *[MaxLevel]*node, rank *[MaxLevel]int) {
+	x := &l.head
+	r := 0
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && x.levels[i].next.key.CompareTo(k) < 0 {
+			r += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+		rank[i] = r
+	}
+}
+
+// countLess returns the number of keys which are less than k, or less than or equal to k when inclusive is set
+// a zero-value list has no level in use, so nothing is visited
+func (l *SkipList) countLess(k Key, inclusive bool) int {
+	x := &l.head
+	r := 0
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil {
+			cmp := x.levels[i].next.key.CompareTo(k)
+			if cmp > 0 || cmp == 0 && !inclusive {
+				break
+			}
+			r += x.levels[i].span
+			x = x.levels[i].next
+		}
+	}
+	return r
+}
+
+// find returns the node holding k, or nil
+func (l *SkipList) find(k Key) *node {
+	if l.head.levels == nil {
+		return nil
+	}
+	var update [MaxLevel]*node
+	var rank [MaxLevel]int
+	l.findLess(k, &update, &rank)
+	x := update[0].levels[0].next
+	if x != nil && x.key.CompareTo(k) == 0 {
+		return x
+	}
+	return nil
+}
+
+// Init initializes the map, it deletes all keys from the map
+func (l *SkipList) Init() {
+	l.head.levels = nil
+	l.tail = nil
+	l.size = 0
+	l.checkAndInit()
+}
+
+// Get returns the Value associated with k, nil if k is not in the map or k is nil
+func (l *SkipList) Get(k Key) Value {
+	if k == nil {
+		return nil
+	}
+	x := l.find(k)
+	if x == nil {
+		return nil
+	}
+	return x.value
+}
+
+// Put stores v associated with k, replacing the previous Value of k
+// When k is nil, the method will directly return and no values will be stored
+func (l *SkipList) Put(k Key, v Value) {
+	if k == nil {
+		return
+	}
+	var update [MaxLevel]*node
+	var rank [MaxLevel]int
+	l.checkAndInit()
+	l.findLess(k, &update, &rank)
+	if x := update[0].levels[0].next; x != nil && x.key.CompareTo(k) == 0 {
+		x.value = v
+		return
+	}
+
+	lvl := l.randomLevel()
+	for i := l.level; i < lvl; i++ {
+		update[i] = &l.head
+		rank[i] = 0
+		l.head.levels[i].span = l.size
+	}
+	l.level = max(l.level, lvl)
+
+	x := &node{key: k, value: v, levels: make([]link, lvl)}
+	for i := 0; i < lvl; i++ {
+		// rank[0] - rank[i] is the distance from update[i] to update[0], x is one step after update[0]
+		skipped := rank[0] - rank[i]
+		x.levels[i].next = update[i].levels[i].next
+		x.levels[i].span = update[i].levels[i].span - skipped
+		update[i].levels[i].next = x
+		update[i].levels[i].span = skipped + 1
+	}
+	for i := lvl; i < l.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != &l.head {
+		x.prev = update[0]
+	}
+	if next := x.levels[0].next; next != nil {
+		next.prev = x
+	} else {
+		l.tail = x
+	}
+	l.size++
+}
+
+// Delete deletes the Value associated with Key k from the map if the key exists
+func (l *SkipList) Delete(k Key) {
+	if k == nil {
+		return
+	}
+	var update [MaxLevel]*node
+	var rank [MaxLevel]int
+	l.checkAndInit()
+	l.findLess(k, &update, &rank)
+	x := update[0].levels[0].next
+	if x == nil || x.key.CompareTo(k) != 0 {
+		return
+	}
+
+	for i := 0; i < l.level; i++ {
+		if update[i].levels[i].next == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].next = x.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if next := x.levels[0].next; next != nil {
+		next.prev = x.prev
+	} else {
+		l.tail = x.prev
+	}
+	for l.level > 1 && l.head.levels[l.level-1].next == nil {
+		l.head.levels[l.level-1].span = 0
+		l.level--
+	}
+	l.size--
+}
+
+// Contains returns whether a Value associated with Key k is stored in the map
+func (l *SkipList) Contains(k Key) bool {
+	if k == nil {
+		return false
+	}
+	return l.find(k) != nil
+}
+
+// IsEmpty returns whether the map is empty
+func (l *SkipList) IsEmpty() bool {
+	return l.size == 0
+}
+
+// Size returns the number of Key in the map
+func (l *SkipList) Size() int {
+	return l.size
+}
+
+// Min returns the minimum key of the map
+func (l *SkipList) Min() Key {
+	if l.size == 0 {
+		return nil
+	}
+	return l.head.levels[0].next.key
+}
+
+// Max returns the maximum key of the map
+func (l *SkipList) Max() Key {
+	if l.tail == nil {
+		return nil
+	}
+	return l.tail.key
+}
+
+// Floor returns the maximum key which is less than or equals k
+func (l *SkipList) Floor(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	if x := l.atRank(l.countLess(k, true)); x != nil {
+		return x.key
+	}
+	return nil
+}
+
+// Ceiling returns the minimum key which is greater than or equals k
+func (l *SkipList) Ceiling(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	if x := l.atRank(l.countLess(k, false) + 1); x != nil {
+		return x.key
+	}
+	return nil
+}
+
+// atRank returns the node at position r, where the first node has position 1, nil if there is none
+func (l *SkipList) atRank(r int) *node {
+	if r < 1 || r > l.size {
+		return nil
+	}
+	x := &l.head
+	traversed := 0
+	for i := l.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= r {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == r {
+			return x
+		}
+	}
+	return nil
+}
+
+// Select returns the key which ranks r-th (staring from 0, in order of Key's comparator) in the map
+// nil is returned when r < 0 or r >= l.Size()
+func (l *SkipList) Select(r int) Key {
+	if x := l.atRank(r + 1); x != nil {
+		return x.key
+	}
+	return nil
+}
+
+// If Key k is in the map, the rank of k is returned (staring from 0, in order of Key's comparator)
+// otherwise -1 is returned
+func (l *SkipList) Rank(k Key) int {
+	if k == nil {
+		return -1
+	}
+	r := l.countLess(k, false)
+	if x := l.atRank(r + 1); x != nil && x.key.CompareTo(k) == 0 {
+		return r
+	}
+	return -1
+}
+
+// DeleteMin deletes the minimum key from the map
+func (l *SkipList) DeleteMin() {
+	l.Delete(l.Min())
+}
+
+// DeleteMax deletes the maximum key from the map
+func (l *SkipList) DeleteMax() {
+	l.Delete(l.Max())
+}
+
+// SizeBetween returns the number of Key that is in interval [lb, ub] (both sides included)
+func (l *SkipList) SizeBetween(lb, ub Key) int {
+	if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+		return 0
+	}
+	return l.countLess(ub, true) - l.countLess(lb, false)
+}
+
+// Keys returns a sorted slice of all keys in the map
+func (l *SkipList) Keys() []Key {
+	keys := make([]Key, 0, l.size)
+	for k := range l.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// KeysBetween returns a sorted slice of Key, for each element k, k >= lb and k <= ub hold
+// if lb == nil or ub == nil, empty slice is returned
+func (l *SkipList) KeysBetween(lb, ub Key) []Key {
+	keys := make([]Key, 0)
+	for k := range l.Range(lb, ub) {
+		keys = append(keys, k)
+	}
+	return keys
+}
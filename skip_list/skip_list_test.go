@@ -0,0 +1,235 @@
+package skip_list
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ rb_tree.SortedMap = (*SkipList)(nil)
+
+type intKey int
+
+func (k intKey) CompareTo(o Key) int {
+	return int(k) - int(o.(intKey))
+}
+
+func keysOf(ks ...int) []Key {
+	res := make([]Key, len(ks))
+	for i, k := range ks {
+		res[i] = intKey(k)
+	}
+	return res
+}
+
+// checkSpans verifies every link against the bottom level, where each span is 1
+func checkSpans(t *testing.T, l *SkipList) {
+	pos := map[*node]int{&l.head: 0}
+	i := 0
+	for x := l.head.levels[0].next; x != nil; x = x.levels[0].next {
+		i++
+		pos[x] = i
+	}
+	assert.Equal(t, l.size, i)
+	for lvl := 0; lvl < l.level; lvl++ {
+		for x := &l.head; x != nil; x = x.levels[lvl].next {
+			next := x.levels[lvl].next
+			if next == nil {
+				assert.Equal(t, l.size-pos[x], x.levels[lvl].span, "span to the end at level %d", lvl)
+			} else {
+				assert.Equal(t, pos[next]-pos[x], x.levels[lvl].span, "span at level %d", lvl)
+			}
+		}
+	}
+}
+
+func TestSkipList_ZeroValue(t *testing.T) {
+	var l SkipList
+	assert.True(t, l.IsEmpty())
+	assert.Nil(t, l.Min())
+	assert.Nil(t, l.Max())
+	assert.Nil(t, l.Get(intKey(1)))
+	assert.Nil(t, l.Select(0))
+	assert.Equal(t, -1, l.Rank(intKey(1)))
+	assert.Empty(t, l.Keys())
+	l.DeleteMin()
+	l.DeleteMax()
+
+	l.Put(nil, 1)
+	assert.Zero(t, l.Size())
+	l.Put(intKey(1), "a")
+	assert.Equal(t, "a", l.Get(intKey(1)))
+	l.Init()
+	assert.True(t, l.IsEmpty())
+	assert.False(t, l.Contains(intKey(1)))
+}
+
+// reading a zero-value list does not set it up, so concurrent readers do not race, run with -race
+func TestSkipList_ZeroValueReaders(t *testing.T) {
+	var l SkipList
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, l.Get(intKey(1)))
+			assert.False(t, l.Contains(intKey(1)))
+			assert.Nil(t, l.Floor(intKey(1)))
+			assert.Nil(t, l.Ceiling(intKey(1)))
+			assert.Equal(t, -1, l.Rank(intKey(1)))
+			assert.Zero(t, l.SizeBetween(intKey(0), intKey(9)))
+			assert.Empty(t, l.KeysBetween(intKey(0), intKey(9)))
+		}()
+	}
+	wg.Wait()
+	assert.Nil(t, l.head.levels)
+}
+
+func TestSkipList(t *testing.T) {
+	l := New(rand.NewSource(1))
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		l.Put(intKey(k), k*10)
+	}
+	l.Put(intKey(3), "three")
+	assert.Equal(t, 5, l.Size())
+	assert.Equal(t, "three", l.Get(intKey(3)))
+	assert.Equal(t, 90, l.Get(intKey(9)))
+	assert.Nil(t, l.Get(intKey(4)))
+	assert.Equal(t, keysOf(1, 3, 5, 7, 9), l.Keys())
+	assert.Equal(t, intKey(1), l.Min())
+	assert.Equal(t, intKey(9), l.Max())
+
+	assert.Equal(t, intKey(5), l.Floor(intKey(6)))
+	assert.Equal(t, intKey(5), l.Floor(intKey(5)))
+	assert.Nil(t, l.Floor(intKey(0)))
+	assert.Equal(t, intKey(7), l.Ceiling(intKey(6)))
+	assert.Nil(t, l.Ceiling(intKey(10)))
+
+	assert.Equal(t, intKey(7), l.Select(3))
+	assert.Nil(t, l.Select(5))
+	assert.Nil(t, l.Select(-1))
+	assert.Equal(t, 3, l.Rank(intKey(7)))
+	assert.Equal(t, -1, l.Rank(intKey(6)))
+
+	assert.Equal(t, 3, l.SizeBetween(intKey(2), intKey(7)))
+	assert.Equal(t, 0, l.SizeBetween(intKey(7), intKey(2)))
+	assert.Equal(t, 0, l.SizeBetween(nil, intKey(2)))
+	assert.Equal(t, keysOf(3, 5, 7), l.KeysBetween(intKey(2), intKey(7)))
+	assert.Equal(t, []Key{}, l.KeysBetween(intKey(2), nil))
+
+	l.DeleteMin()
+	l.DeleteMax()
+	l.Delete(intKey(5))
+	l.Delete(intKey(4))
+	assert.Equal(t, keysOf(3, 7), l.Keys())
+	assert.Equal(t, intKey(7), l.Max())
+	checkSpans(t, l)
+}
+
+func TestSkipList_Iter(t *testing.T) {
+	l := New(rand.NewSource(2))
+	for i := 0; i < 10; i++ {
+		l.Put(intKey(i), i)
+	}
+	var keys []Key
+	for k, v := range l.Backward() {
+		assert.Equal(t, int(k.(intKey)), v)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, keysOf(9, 8, 7, 6, 5, 4, 3, 2, 1, 0), keys)
+
+	keys = keys[:0]
+	for k := range l.Range(intKey(3), intKey(6)) {
+		keys = append(keys, k)
+		if k == intKey(5) {
+			break
+		}
+	}
+	assert.Equal(t, keysOf(3, 4, 5), keys)
+}
+
+// shape returns the level of every node in order
+func shape(l *SkipList) []int {
+	var res []int
+	for x := l.head.levels[0].next; x != nil; x = x.levels[0].next {
+		res = append(res, len(x.levels))
+	}
+	return res
+}
+
+func TestSkipList_Seed(t *testing.T) {
+	a, b := New(rand.NewSource(42)), New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		a.Put(intKey(i), nil)
+		b.Put(intKey(i), nil)
+	}
+	assert.Equal(t, shape(a), shape(b))
+	assert.Greater(t, a.level, 1)
+}
+
+func TestSkipList_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	l := New(rand.NewSource(7))
+	ref := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		switch r.Intn(4) {
+		case 0:
+			l.Delete(intKey(k))
+			delete(ref, k)
+		case 1:
+			if len(ref) > 0 {
+				min := l.Min().(intKey)
+				l.DeleteMin()
+				delete(ref, int(min))
+			}
+		default:
+			l.Put(intKey(k), i)
+			ref[k] = i
+		}
+	}
+	checkSpans(t, l)
+
+	sorted := make([]int, 0, len(ref))
+	for k := range ref {
+		sorted = append(sorted, k)
+	}
+	slices.Sort(sorted)
+	assert.Equal(t, keysOf(sorted...), l.Keys())
+	for i, k := range sorted {
+		assert.Equal(t, ref[k], l.Get(intKey(k)))
+		assert.Equal(t, i, l.Rank(intKey(k)))
+		assert.Equal(t, intKey(k), l.Select(i))
+	}
+	for i := 0; i < 100; i++ {
+		lb, ub := r.Intn(500), r.Intn(500)
+		lo, _ := slices.BinarySearch(sorted, lb)
+		hi, found := slices.BinarySearch(sorted, ub)
+		if found {
+			hi++
+		}
+		want := max(hi-lo, 0)
+		assert.Equal(t, want, l.SizeBetween(intKey(lb), intKey(ub)))
+		assert.Len(t, l.KeysBetween(intKey(lb), intKey(ub)), want)
+	}
+}
+
+func benchmarkPut(b *testing.B, m rb_tree.SortedMap) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	b.ResetTimer()
+	for _, k := range keys {
+		m.Put(intKey(k), k)
+	}
+}
+
+func BenchmarkSkipList_Put(b *testing.B) {
+	benchmarkPut(b, New(rand.NewSource(1)))
+}
+
+func BenchmarkRBTree_Put(b *testing.B) {
+	benchmarkPut(b, &rb_tree.RBTree{})
+}
@@ -0,0 +1,222 @@
+package avl_tree
+
+import (
+	"iter"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+)
+
+// mapNode is a node of an AvlMap, the key of its Node points at its entry, so a node takes a single allocation
+type mapNode struct {
+	Node
+	entry mapEntry
+}
+
+// mapEntry is the key of the Node of a mapNode, it orders the nodes by the rb_tree.Key and carries the value along
+type mapEntry struct {
+	key   rb_tree.Key
+	value rb_tree.Value
+}
+
+func (e *mapEntry) CompareTo(o Key) int {
+	return e.key.CompareTo(o.(*mapEntry).key)
+}
+
+func entryOf(x *Node) *mapEntry {
+	return x.key.(*mapEntry)
+}
+
+// mapOrder returns the order in which search and countBefore look for k in the nodes of an AvlMap
+func mapOrder(k rb_tree.Key) func(*Node) int {
+	return func(x *Node) int {
+		return k.CompareTo(entryOf(x).key)
+	}
+}
+
+// AvlMap is a sorted map kept in an AVL tree, it implements rb_tree.SortedMap with the same semantics as RBTree
+// an AVL tree is more strictly balanced than a red-black tree, so lookups visit fewer nodes while updates rotate more,
+// which pays off when reads outnumber writes
+type AvlMap struct {
+	root *Node
+}
+
+// NewMap returns an empty map, the zero value of AvlMap is also ready to use
+func NewMap() *AvlMap {
+	return &AvlMap{}
+}
+
+// Init initializes the map, it deletes all keys from the map
+func (m *AvlMap) Init() {
+	m.root = nil
+}
+
+// Get returns the Value associated with k, nil if k is not in the map or k is nil
+func (m *AvlMap) Get(k rb_tree.Key) rb_tree.Value {
+	if k == nil {
+		return nil
+	}
+	x := m.root.search(mapOrder(k))
+	if x == nil {
+		return nil
+	}
+	return entryOf(x).value
+}
+
+// Put stores v associated with k, replacing the previous Value of k
+// When k is nil, the method will directly return and no values will be stored
+func (m *AvlMap) Put(k rb_tree.Key, v rb_tree.Value) {
+	if k == nil {
+		return
+	}
+	if x := m.root.search(mapOrder(k)); x != nil {
+		entryOf(x).value = v
+		return
+	}
+	n := &mapNode{entry: mapEntry{key: k, value: v}}
+	n.Node = Node{height: 1, size: 1, key: &n.entry}
+	m.root = m.root.insert(&n.Node)
+}
+
+// Delete deletes the Value associated with Key k from the map if the key exists
+func (m *AvlMap) Delete(k rb_tree.Key) {
+	if k == nil {
+		return
+	}
+	if x := m.root.search(mapOrder(k)); x != nil {
+		m.root = m.root.remove(x.key)
+	}
+}
+
+// Contains returns whether a Value associated with Key k is stored in the map
+func (m *AvlMap) Contains(k rb_tree.Key) bool {
+	if k == nil {
+		return false
+	}
+	return m.root.search(mapOrder(k)) != nil
+}
+
+// IsEmpty returns whether the map is empty
+func (m *AvlMap) IsEmpty() bool {
+	return m.root == nil
+}
+
+// Size returns the number of Key in the map
+func (m *AvlMap) Size() int {
+	return m.root.getSize()
+}
+
+// Min returns the minimum key of the map
+func (m *AvlMap) Min() rb_tree.Key {
+	if m.root == nil {
+		return nil
+	}
+	return entryOf(m.root.findMin()).key
+}
+
+// Max returns the maximum key of the map
+func (m *AvlMap) Max() rb_tree.Key {
+	if m.root == nil {
+		return nil
+	}
+	return entryOf(m.root.findMax()).key
+}
+
+// Floor returns the maximum key which is less than or equals k
+func (m *AvlMap) Floor(k rb_tree.Key) rb_tree.Key {
+	if k == nil {
+		return nil
+	}
+	return m.Select(m.root.countBefore(mapOrder(k), true) - 1)
+}
+
+// Ceiling returns the minimum key which is greater than or equals k
+func (m *AvlMap) Ceiling(k rb_tree.Key) rb_tree.Key {
+	if k == nil {
+		return nil
+	}
+	return m.Select(m.root.countBefore(mapOrder(k), false))
+}
+
+// Select returns the key which ranks r-th (staring from 0, in order of Key's comparator) in the map
+// nil is returned when r < 0 or r >= m.Size()
+func (m *AvlMap) Select(r int) rb_tree.Key {
+	if r < 0 || r >= m.Size() {
+		return nil
+	}
+	return entryOf(m.root.getNth(r)).key
+}
+
+// If Key k is in the map, the rank of k is returned (staring from 0, in order of Key's comparator)
+// otherwise -1 is returned
+func (m *AvlMap) Rank(k rb_tree.Key) int {
+	if !m.Contains(k) {
+		return -1
+	}
+	return m.root.countBefore(mapOrder(k), false)
+}
+
+// DeleteMin deletes the minimum key from the map
+func (m *AvlMap) DeleteMin() {
+	if m.root != nil {
+		m.root = m.root.remove(m.root.findMin().key)
+	}
+}
+
+// DeleteMax deletes the maximum key from the map
+func (m *AvlMap) DeleteMax() {
+	if m.root != nil {
+		m.root = m.root.remove(m.root.findMax().key)
+	}
+}
+
+// SizeBetween returns the number of Key that is in interval [lb, ub] (both sides included)
+func (m *AvlMap) SizeBetween(lb, ub rb_tree.Key) int {
+	if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+		return 0
+	}
+	return m.root.countBefore(mapOrder(ub), true) - m.root.countBefore(mapOrder(lb), false)
+}
+
+// Keys returns a sorted slice of all keys in the map
+func (m *AvlMap) Keys() []rb_tree.Key {
+	keys := make([]rb_tree.Key, 0, m.Size())
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// KeysBetween returns a sorted slice of Key, for each element k, k >= lb and k <= ub hold
+// if lb == nil or ub == nil, empty slice is returned
+func (m *AvlMap) KeysBetween(lb, ub rb_tree.Key) []rb_tree.Key {
+	keys := make([]rb_tree.Key, 0)
+	for k := range m.Range(lb, ub) {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// All returns an iterator over the key-value pairs in ascending order of keys
+// the map must not be modified during the iteration
+func (m *AvlMap) All() iter.Seq2[rb_tree.Key, rb_tree.Value] {
+	return func(yield func(rb_tree.Key, rb_tree.Value) bool) {
+		m.root.ascend(func(x *Node) bool {
+			e := entryOf(x)
+			return yield(e.key, e.value)
+		})
+	}
+}
+
+// Range returns an iterator over the key-value pairs whose keys are in [lb, ub], in ascending order
+// like KeysBetween, nothing is yielded if lb or ub is nil
+func (m *AvlMap) Range(lb, ub rb_tree.Key) iter.Seq2[rb_tree.Key, rb_tree.Value] {
+	return func(yield func(rb_tree.Key, rb_tree.Value) bool) {
+		if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+			return
+		}
+		m.root.ascendRange(mapOrder(lb), mapOrder(ub), func(x *Node) bool {
+			e := entryOf(x)
+			return yield(e.key, e.value)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package avl_tree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ rb_tree.SortedMap = (*AvlMap)(nil)
+
+type mapKey int
+
+func (a mapKey) CompareTo(b rb_tree.Key) int {
+	return int(a) - int(b.(mapKey))
+}
+
+func mapKeys(ks ...int) []rb_tree.Key {
+	res := make([]rb_tree.Key, len(ks))
+	for i, k := range ks {
+		res[i] = mapKey(k)
+	}
+	return res
+}
+
+func TestAvlMap(t *testing.T) {
+	m := NewMap()
+	assert.True(t, m.IsEmpty())
+	assert.Nil(t, m.Min())
+	assert.Nil(t, m.Max())
+	assert.Nil(t, m.Get(mapKey(1)))
+	assert.Nil(t, m.Floor(mapKey(1)))
+	m.DeleteMin()
+	m.DeleteMax()
+	m.Put(nil, 1)
+	assert.Zero(t, m.Size())
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		m.Put(mapKey(k), k*10)
+	}
+	m.Put(mapKey(3), "three")
+	assert.Equal(t, 5, m.Size())
+	assert.Equal(t, "three", m.Get(mapKey(3)))
+	assert.Nil(t, m.Get(nil))
+	assert.True(t, m.Contains(mapKey(9)))
+	assert.False(t, m.Contains(mapKey(4)))
+	assert.Equal(t, mapKeys(1, 3, 5, 7, 9), m.Keys())
+
+	assert.Equal(t, mapKey(5), m.Floor(mapKey(6)))
+	assert.Equal(t, mapKey(7), m.Ceiling(mapKey(6)))
+	assert.Nil(t, m.Ceiling(mapKey(10)))
+	assert.Equal(t, mapKey(7), m.Select(3))
+	assert.Nil(t, m.Select(5))
+	assert.Equal(t, 3, m.Rank(mapKey(7)))
+	assert.Equal(t, -1, m.Rank(mapKey(6)))
+	assert.Equal(t, 3, m.SizeBetween(mapKey(2), mapKey(7)))
+	assert.Equal(t, mapKeys(3, 5, 7), m.KeysBetween(mapKey(2), mapKey(7)))
+	assert.Equal(t, []rb_tree.Key{}, m.KeysBetween(nil, mapKey(7)))
+
+	m.DeleteMin()
+	m.DeleteMax()
+	m.Delete(mapKey(5))
+	m.Delete(mapKey(4))
+	assert.Equal(t, mapKeys(3, 7), m.Keys())
+	checkAvl(t, m.root)
+
+	m.Init()
+	assert.True(t, m.IsEmpty())
+}
+
+// the map behaves as RBTree under the same random operations
+func TestAvlMap_AsRBTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := NewMap()
+	var ref rb_tree.RBTree
+	for i := 0; i < 5000; i++ {
+		k := mapKey(r.Intn(1000))
+		switch r.Intn(6) {
+		case 0:
+			m.Delete(k)
+			ref.Delete(k)
+		case 1:
+			m.DeleteMin()
+			ref.DeleteMin()
+		case 2:
+			m.DeleteMax()
+			ref.DeleteMax()
+		default:
+			m.Put(k, i)
+			ref.Put(k, i)
+		}
+	}
+	checkAvl(t, m.root)
+	assert.Equal(t, ref.Keys(), m.Keys())
+	for i := 0; i < 200; i++ {
+		k := mapKey(r.Intn(1100) - 50)
+		ub := mapKey(r.Intn(1100) - 50)
+		assert.Equal(t, ref.Get(k), m.Get(k))
+		assert.Equal(t, ref.Floor(k), m.Floor(k))
+		assert.Equal(t, ref.Ceiling(k), m.Ceiling(k))
+		assert.Equal(t, ref.Rank(k), m.Rank(k))
+		assert.Equal(t, ref.Select(int(k)), m.Select(int(k)))
+		assert.Equal(t, ref.KeysBetween(k, ub), m.KeysBetween(k, ub))
+		// RBTree.SizeBetween miscounts bounds which are not in the tree, so the count is checked against the keys
+		assert.Equal(t, len(m.KeysBetween(k, ub)), m.SizeBetween(k, ub))
+	}
+	keys := m.Keys()
+	assert.True(t, slices.IsSortedFunc(keys, rb_tree.Compare))
+}
+
+// a new key takes one allocation for its node, updating a key and reading take none
+func TestAvlMap_Allocs(t *testing.T) {
+	m := NewMap()
+	keys := mapKeys(0, 1, 2, 3, 4, 5, 6, 7)
+	for _, k := range keys {
+		m.Put(k, 0)
+	}
+	k := keys[3]
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		m.Put(k, 1)
+		m.Get(k)
+		m.Floor(k)
+	}))
+
+	k = mapKey(100)
+	assert.Equal(t, 1.0, testing.AllocsPerRun(100, func() {
+		m.Delete(k)
+		m.Put(k, 1)
+	}))
+}
+
+// a read-mostly workload, 15 reads per write, on an AvlMap and an RBTree
+func benchmarkReadMostly(b *testing.B, m rb_tree.SortedMap) {
+	const size = 1 << 14
+	r := rand.New(rand.NewSource(1))
+	for _, k := range r.Perm(size) {
+		m.Put(mapKey(k), k)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := mapKey(r.Intn(size))
+		if i%16 == 0 {
+			m.Put(k, i)
+		} else {
+			m.Get(k)
+		}
+	}
+}
+
+func BenchmarkAvlMap_ReadMostly(b *testing.B) {
+	benchmarkReadMostly(b, NewMap())
+}
+
+func BenchmarkRBTree_ReadMostly(b *testing.B) {
+	benchmarkReadMostly(b, &rb_tree.RBTree{})
+}
@@ -4,13 +4,20 @@ type Node struct {
 	left   *Node
 	right  *Node
 	height int
+	size   int // number of keys in the subtree
 	key    Key
 }
 
+// Key is implemented by the keys of an AvlTree, for a tree without interface keys see avlgen.AvlTree
 type Key interface {
 	CompareTo(Key) int
 }
 
+// Compare returns a.CompareTo(b), so avlgen.New(Compare) builds a typed tree over existing Key implementations
+func Compare(a, b Key) int {
+	return a.CompareTo(b)
+}
+
 type AvlTree struct {
 	root *Node
 	size int
@@ -40,6 +47,7 @@ func (t *AvlTree) Add(k Key) bool {
 			left:   nil,
 			right:  nil,
 			height: 0,
+			size:   1,
 			key:    k,
 		}
 		t.size = 1
@@ -47,7 +55,7 @@ func (t *AvlTree) Add(k Key) bool {
 		if t.root.find(k) != nil {
 			return false
 		}
-		t.root = t.root.insert(k)
+		t.root = t.root.insert(newNode(k))
 		t.size++
 	}
 	return true
@@ -87,11 +95,106 @@ func (t *AvlTree) Contains(k Key) bool {
 	return node != nil
 }
 
+// Select returns the key which ranks i-th (starting from 0) in the tree, nil is returned when i < 0 or i >= t.Size()
+func (t *AvlTree) Select(i int) Key {
+	if i < 0 || i >= t.size {
+		return nil
+	}
+	return t.root.getNth(i).key
+}
+
+// Rank returns the number of keys less than k if k is in the tree, otherwise -1 is returned
+func (t *AvlTree) Rank(k Key) int {
+	if k == nil || t.root.find(k) == nil {
+		return -1
+	}
+	return t.root.countLess(k, false)
+}
+
+// CountBetween returns the number of keys in [lb, ub] (both sides included)
+// 0 is returned if lb or ub is nil, or lb is greater than ub
+func (t *AvlTree) CountBetween(lb, ub Key) int {
+	if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+		return 0
+	}
+	return t.root.countLess(ub, true) - t.root.countLess(lb, false)
+}
+
+// Median returns the lower median of the keys, which is the key of rank (Size()-1)/2, nil is returned when the tree is empty
+func (t *AvlTree) Median() Key {
+	return t.Select((t.size - 1) / 2)
+}
+
+// Floor returns the greatest key less than or equal to k, nil is returned if there is no such key
+func (t *AvlTree) Floor(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	return t.Select(t.root.countLess(k, true) - 1)
+}
+
+// Ceiling returns the least key greater than or equal to k, nil is returned if there is no such key
+func (t *AvlTree) Ceiling(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	return t.Select(t.root.countLess(k, false))
+}
+
+// Lower returns the greatest key strictly less than k, nil is returned if there is no such key
+func (t *AvlTree) Lower(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	return t.Select(t.root.countLess(k, false) - 1)
+}
+
+// Higher returns the least key strictly greater than k, nil is returned if there is no such key
+func (t *AvlTree) Higher(k Key) Key {
+	if k == nil {
+		return nil
+	}
+	return t.Select(t.root.countLess(k, true))
+}
+
+// KeysBetween returns a sorted slice of the keys in [lb, ub]
+// an empty slice is returned if lb or ub is nil, or lb is greater than ub
+func (t *AvlTree) KeysBetween(lb, ub Key) []Key {
+	res := make([]Key, 0)
+	for k := range t.Range(lb, ub) {
+		res = append(res, k)
+	}
+	return res
+}
+
+// PollFirst removes the minimum key from the tree and returns it, nil is returned when the tree is empty
+func (t *AvlTree) PollFirst() Key {
+	if t.root == nil {
+		return nil
+	}
+	k := t.root.findMin().key
+	t.root = t.root.remove(k)
+	t.size--
+	return k
+}
+
+// PollLast removes the maximum key from the tree and returns it, nil is returned when the tree is empty
+func (t *AvlTree) PollLast() Key {
+	if t.root == nil {
+		return nil
+	}
+	k := t.root.findMax().key
+	t.root = t.root.remove(k)
+	t.size--
+	return k
+}
+
 func newNode(key Key) *Node {
 	return &Node{
 		left:   nil,
 		right:  nil,
 		height: 1,
+		size:   1,
 		key:    key,
 	}
 }
@@ -115,6 +218,21 @@ func (t *Node) calcHeight() int {
 	return t.height
 }
 
+func (t *Node) getSize() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+func (t *Node) calcSize() int {
+	if t == nil {
+		return 0
+	}
+	t.size = t.left.getSize() + t.right.getSize() + 1
+	return t.size
+}
+
 func (t *Node) adjust() *Node {
 	if t == nil {
 		return nil
@@ -135,6 +253,7 @@ func (t *Node) adjust() *Node {
 	}
 
 	t.calcHeight()
+	t.calcSize()
 	return t
 }
 
@@ -166,6 +285,9 @@ func rotateLeft(t *Node) *Node {
 	res.getLeft().calcHeight()
 	res.getRight().calcHeight()
 	res.calcHeight()
+	res.getLeft().calcSize()
+	res.getRight().calcSize()
+	res.calcSize()
 	return res
 }
 
@@ -183,18 +305,22 @@ func rotateRight(t *Node) *Node {
 	res.getLeft().calcHeight()
 	res.getRight().calcHeight()
 	res.calcHeight()
+	res.getLeft().calcSize()
+	res.getRight().calcSize()
+	res.calcSize()
 	return res
 }
 
-func (t *Node) insert(k Key) *Node {
+// insert links the node n into the subtree
+func (t *Node) insert(n *Node) *Node {
 	if t == nil {
-		return newNode(k)
+		return n
 	}
-	res := k.CompareTo(t.key)
+	res := n.key.CompareTo(t.key)
 	if res < 0 {
-		t.left = t.left.insert(k)
+		t.left = t.left.insert(n)
 	} else if res > 0 {
-		t.right = t.right.insert(k)
+		t.right = t.right.insert(n)
 	} else {
 		return t // existing key not accepted
 	}
@@ -240,15 +366,28 @@ func (t *Node) remove(k Key) *Node {
 			newRoot = t.left
 			t.left = nil
 		} else {
+			// the predecessor takes the place of t, so that every node keeps its key
 			pred := t.left.findMax()
-			t.left = t.left.remove(pred.key)
-			t.key = pred.key
+			pred.left = t.left.removeMax()
+			pred.right = t.right
+			newRoot = pred
+			t.left = nil
+			t.right = nil
 		}
 	}
 
 	return newRoot.adjust()
 }
 
+// removeMax unlinks the node of the maximum key from the subtree
+func (t *Node) removeMax() *Node {
+	if t.right == nil {
+		return t.left
+	}
+	t.right = t.right.removeMax()
+	return t.adjust()
+}
+
 func (t *AvlTree) apply(f func(Key)) {
 	if t.root == nil {
 		return
@@ -276,15 +415,62 @@ func (t *Node) traverse(f func(Key)) {
 }
 
 func (t *Node) find(k Key) *Node {
-	if t == nil {
-		return nil
+	return t.search(orderOf(k))
+}
+
+// orderOf returns the order in which search and countBefore look for k
+func orderOf(k Key) func(*Node) int {
+	return func(x *Node) int {
+		return k.CompareTo(x.key)
 	}
-	res := k.CompareTo(t.key)
-	if res < 0 {
-		return t.left.find(k)
-	} else if res > 0 {
-		return t.right.find(k)
-	} else {
-		return t
+}
+
+// search returns the node for which order returns 0, order tells whether the sought key is less or greater than the key of a node
+func (t *Node) search(order func(*Node) int) *Node {
+	for t != nil {
+		res := order(t)
+		if res < 0 {
+			t = t.left
+		} else if res > 0 {
+			t = t.right
+		} else {
+			return t
+		}
+	}
+	return nil
+}
+
+// getNth returns the node of rank n in the subtree, 0 <= n < t.size must hold
+func (t *Node) getNth(n int) *Node {
+	for {
+		rank := t.left.getSize()
+		if n < rank {
+			t = t.left
+		} else if n > rank {
+			n -= rank + 1
+			t = t.right
+		} else {
+			return t
+		}
+	}
+}
+
+// countLess returns the number of keys less than k, or less than or equal to k when inclusive is set
+func (t *Node) countLess(k Key, inclusive bool) int {
+	return t.countBefore(orderOf(k), inclusive)
+}
+
+// countBefore is countLess for the key sought by order
+func (t *Node) countBefore(order func(*Node) int, inclusive bool) int {
+	n := 0
+	for t != nil {
+		res := order(t)
+		if res < 0 || res == 0 && !inclusive {
+			t = t.left
+		} else {
+			n += t.left.getSize() + 1
+			t = t.right
+		}
 	}
+	return n
 }
@@ -244,3 +244,178 @@ func TestAvlTree(t *testing.T) {
 	tree.Init()
 
 }
+
+func TestAvlTree_All(t *testing.T) {
+	var tree AvlTree
+	for _, x := range []int{5, 3, 8, 1, 4, 7, 9} {
+		tree.Add(intKey(x))
+	}
+
+	var keys []Key
+	for k := range tree.All() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, tree.ToSlice(), keys)
+
+	keys = nil
+	for k := range tree.Descend() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []Key{intKey(9), intKey(8), intKey(7), intKey(5), intKey(4), intKey(3), intKey(1)}, keys)
+
+	keys = nil
+	for k := range tree.All() {
+		if k.(intKey) > 4 {
+			break
+		}
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []Key{intKey(1), intKey(3), intKey(4)}, keys)
+
+	var empty AvlTree
+	for range empty.All() {
+		t.Fatal("empty tree yielded a key")
+	}
+}
+
+func TestAvlTree_Range(t *testing.T) {
+	var tree AvlTree
+	for i := 0; i < 100; i += 2 {
+		tree.Add(intKey(i))
+	}
+	collect := func(lb, ub Key, limit int) []Key {
+		keys := []Key{}
+		for k := range tree.Range(lb, ub) {
+			if len(keys) == limit {
+				break
+			}
+			keys = append(keys, k)
+		}
+		return keys
+	}
+
+	assert.Equal(t, []Key{intKey(10), intKey(12), intKey(14)}, collect(intKey(10), intKey(14), -1))
+	assert.Equal(t, []Key{intKey(10), intKey(12), intKey(14)}, collect(intKey(9), intKey(15), -1))
+	assert.Equal(t, []Key{intKey(0), intKey(2)}, collect(intKey(-5), intKey(99), 2))
+	assert.Len(t, collect(intKey(-5), intKey(1000), -1), 50)
+	assert.Empty(t, collect(intKey(11), intKey(11), -1))
+	assert.Empty(t, collect(intKey(14), intKey(10), -1))
+	assert.Empty(t, collect(nil, intKey(10), -1))
+}
+
+// checkAvl verifies the heights, the sizes and the balance, it returns the height of x
+func checkAvl(t *testing.T, x *Node) int {
+	if x == nil {
+		return 0
+	}
+	l, r := checkAvl(t, x.left), checkAvl(t, x.right)
+	assert.LessOrEqual(t, max(l-r, r-l), 1)
+	assert.Equal(t, max(l, r)+1, x.height)
+	assert.Equal(t, x.left.getSize()+x.right.getSize()+1, x.size)
+	return x.height
+}
+
+func TestAvlTree_Select(t *testing.T) {
+	var tree AvlTree
+	assert.Nil(t, tree.Select(0))
+	assert.Nil(t, tree.Median())
+	assert.Equal(t, -1, tree.Rank(intKey(1)))
+	assert.Equal(t, 0, tree.CountBetween(intKey(0), intKey(10)))
+
+	for _, x := range []int{5, 3, 8, 1, 4, 7, 9} {
+		tree.Add(intKey(x))
+	}
+	assert.Equal(t, intKey(1), tree.Select(0))
+	assert.Equal(t, intKey(5), tree.Select(3))
+	assert.Equal(t, intKey(9), tree.Select(6))
+	assert.Nil(t, tree.Select(7))
+	assert.Nil(t, tree.Select(-1))
+	assert.Equal(t, 4, tree.Rank(intKey(7)))
+	assert.Equal(t, -1, tree.Rank(intKey(6)))
+	assert.Equal(t, -1, tree.Rank(nil))
+	assert.Equal(t, 4, tree.CountBetween(intKey(2), intKey(7)))
+	assert.Equal(t, 7, tree.CountBetween(intKey(1), intKey(9)))
+	assert.Equal(t, 0, tree.CountBetween(intKey(7), intKey(2)))
+	assert.Equal(t, 0, tree.CountBetween(nil, intKey(7)))
+	assert.Equal(t, intKey(5), tree.Median())
+	tree.Add(intKey(10))
+	assert.Equal(t, intKey(5), tree.Median())
+}
+
+func TestAvlTree_SelectRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var tree AvlTree
+	for i := 0; i < 5000; i++ {
+		if k := intKey(r.Intn(1000)); r.Intn(3) == 0 {
+			tree.Remove(k)
+		} else {
+			tree.Add(k)
+		}
+	}
+	checkAvl(t, tree.root)
+	assert.Equal(t, tree.Size(), tree.root.getSize())
+
+	keys := tree.ToSlice()
+	for i, k := range keys {
+		assert.Equal(t, k, tree.Select(i))
+		assert.Equal(t, i, tree.Rank(k))
+	}
+	for i := 0; i < 100; i++ {
+		lb, ub := intKey(r.Intn(1100)-50), intKey(r.Intn(1100)-50)
+		n := 0
+		for range tree.Range(lb, ub) {
+			n++
+		}
+		assert.Equal(t, n, tree.CountBetween(lb, ub))
+	}
+}
+
+func TestAvlTree_Floor(t *testing.T) {
+	var tree AvlTree
+	assert.Nil(t, tree.Floor(intKey(1)))
+	assert.Nil(t, tree.Higher(intKey(1)))
+	for i := 0; i < 10; i += 2 {
+		tree.Add(intKey(i))
+	}
+
+	assert.Equal(t, intKey(4), tree.Floor(intKey(4)))
+	assert.Equal(t, intKey(4), tree.Floor(intKey(5)))
+	assert.Nil(t, tree.Floor(intKey(-1)))
+	assert.Equal(t, intKey(4), tree.Ceiling(intKey(4)))
+	assert.Equal(t, intKey(6), tree.Ceiling(intKey(5)))
+	assert.Nil(t, tree.Ceiling(intKey(9)))
+
+	assert.Equal(t, intKey(2), tree.Lower(intKey(4)))
+	assert.Equal(t, intKey(4), tree.Lower(intKey(5)))
+	assert.Nil(t, tree.Lower(intKey(0)))
+	assert.Equal(t, intKey(6), tree.Higher(intKey(4)))
+	assert.Equal(t, intKey(6), tree.Higher(intKey(5)))
+	assert.Nil(t, tree.Higher(intKey(8)))
+	assert.Nil(t, tree.Floor(nil))
+
+	assert.Equal(t, []Key{intKey(2), intKey(4), intKey(6)}, tree.KeysBetween(intKey(1), intKey(6)))
+	assert.Equal(t, []Key{}, tree.KeysBetween(intKey(6), intKey(1)))
+	assert.Equal(t, []Key{}, tree.KeysBetween(nil, intKey(6)))
+}
+
+func TestAvlTree_Poll(t *testing.T) {
+	var tree AvlTree
+	assert.Nil(t, tree.PollFirst())
+	assert.Nil(t, tree.PollLast())
+	for _, x := range []int{5, 3, 8, 1, 4, 7, 9} {
+		tree.Add(intKey(x))
+	}
+
+	assert.Equal(t, intKey(1), tree.PollFirst())
+	assert.Equal(t, intKey(9), tree.PollLast())
+	assert.Equal(t, intKey(3), tree.PollFirst())
+	assert.Equal(t, 4, tree.Size())
+	checkAvl(t, tree.root)
+	assert.Equal(t, tree.Size(), tree.root.getSize())
+	assert.Equal(t, []Key{intKey(4), intKey(5), intKey(7), intKey(8)}, tree.ToSlice())
+
+	for tree.PollLast() != nil {
+	}
+	assert.Equal(t, 0, tree.Size())
+	assert.Nil(t, tree.root)
+}
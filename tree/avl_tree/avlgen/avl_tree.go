@@ -0,0 +1,273 @@
+// A typed AVL tree, the keys are stored as K instead of being boxed in an interface like those of avl_tree.AvlTree
+// the tree is built with a comparator, any func(a, b K) int that returns the sign of a - b will do:
+// cmp.Compare for ordered keys, or avl_tree.Compare to keep using keys written for the legacy tree
+package avlgen
+
+import (
+	"cmp"
+	"iter"
+)
+
+type node[K any] struct {
+	left   *node[K]
+	right  *node[K]
+	height int // 1 for a leaf
+	key    K
+}
+
+// AvlTree is a set of keys kept in a height-balanced binary search tree,
+// adding, removing and searching are all O(logN)
+// the zero value is not usable, an AvlTree must be created with New or NewOrdered
+type AvlTree[K any] struct {
+	root *node[K]
+	size int
+	cmp  func(a, b K) int
+}
+
+// New returns an empty tree ordered by cmp
+func New[K any](cmp func(a, b K) int) *AvlTree[K] {
+	return &AvlTree[K]{cmp: cmp}
+}
+
+// NewOrdered returns an empty tree in the natural order of K
+func NewOrdered[K cmp.Ordered]() *AvlTree[K] {
+	return New(cmp.Compare[K])
+}
+
+func (t *AvlTree[K]) Init() *AvlTree[K] {
+	t.root = nil
+	t.size = 0
+	return t
+}
+
+func (t *AvlTree[K]) Size() int {
+	return t.size
+}
+
+// Add adds k to the tree, it returns false if k was already in it
+func (t *AvlTree[K]) Add(k K) bool {
+	var added bool
+	t.root, added = t.insert(t.root, k)
+	if added {
+		t.size++
+	}
+	return added
+}
+
+// Remove removes k from the tree, it returns false if k was not in it
+func (t *AvlTree[K]) Remove(k K) bool {
+	var removed bool
+	t.root, removed = t.remove(t.root, k)
+	if removed {
+		t.size--
+	}
+	return removed
+}
+
+// Min returns the smallest key, ok is false when the tree is empty
+func (t *AvlTree[K]) Min() (k K, ok bool) {
+	if t.root == nil {
+		return k, false
+	}
+	return t.root.findMin().key, true
+}
+
+// Max returns the largest key, ok is false when the tree is empty
+func (t *AvlTree[K]) Max() (k K, ok bool) {
+	if t.root == nil {
+		return k, false
+	}
+	return t.root.findMax().key, true
+}
+
+func (t *AvlTree[K]) Contains(k K) bool {
+	return t.find(k) != nil
+}
+
+// ToSlice returns a slice of stored keys, which are sorted
+func (t *AvlTree[K]) ToSlice() []K {
+	res := make([]K, 0, t.size)
+	for k := range t.All() {
+		res = append(res, k)
+	}
+	return res
+}
+
+func (t *AvlTree[K]) find(k K) *node[K] {
+	x := t.root
+	for x != nil {
+		c := t.cmp(k, x.key)
+		if c < 0 {
+			x = x.left
+		} else if c > 0 {
+			x = x.right
+		} else {
+			return x
+		}
+	}
+	return nil
+}
+
+func (x *node[K]) getHeight() int {
+	if x == nil {
+		return 0
+	}
+	return x.height
+}
+
+func (x *node[K]) calcHeight() {
+	x.height = max(x.left.getHeight(), x.right.getHeight()) + 1
+}
+
+func (x *node[K]) findMin() *node[K] {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+func (x *node[K]) findMax() *node[K] {
+	for x.right != nil {
+		x = x.right
+	}
+	return x
+}
+
+func rotateLeft[K any](x *node[K]) *node[K] {
+	res := x.right
+	x.right = res.left
+	res.left = x
+	x.calcHeight()
+	res.calcHeight()
+	return res
+}
+
+func rotateRight[K any](x *node[K]) *node[K] {
+	res := x.left
+	x.left = res.right
+	res.right = x
+	x.calcHeight()
+	res.calcHeight()
+	return res
+}
+
+// adjust restores the balance of x, whose subtrees differ in height by at most 2, and returns the new root
+func (x *node[K]) adjust() *node[K] {
+	if x == nil {
+		return nil
+	}
+	balance := x.left.getHeight() - x.right.getHeight()
+	if balance > 1 {
+		if x.left.left.getHeight() < x.left.right.getHeight() {
+			x.left = rotateLeft(x.left)
+		}
+		return rotateRight(x)
+	}
+	if balance < -1 {
+		if x.right.left.getHeight() > x.right.right.getHeight() {
+			x.right = rotateRight(x.right)
+		}
+		return rotateLeft(x)
+	}
+	x.calcHeight()
+	return x
+}
+
+func (t *AvlTree[K]) insert(x *node[K], k K) (*node[K], bool) {
+	if x == nil {
+		return &node[K]{key: k, height: 1}, true
+	}
+	var added bool
+	c := t.cmp(k, x.key)
+	if c < 0 {
+		x.left, added = t.insert(x.left, k)
+	} else if c > 0 {
+		x.right, added = t.insert(x.right, k)
+	} else {
+		return x, false // existing key not accepted
+	}
+	return x.adjust(), added
+}
+
+func (t *AvlTree[K]) remove(x *node[K], k K) (*node[K], bool) {
+	if x == nil {
+		return nil, false
+	}
+	removed := true
+	c := t.cmp(k, x.key)
+	if c < 0 {
+		x.left, removed = t.remove(x.left, k)
+	} else if c > 0 {
+		x.right, removed = t.remove(x.right, k)
+	} else {
+		if x.left == nil {
+			return x.right, true
+		}
+		if x.right == nil {
+			return x.left, true
+		}
+		pred := x.left.findMax()
+		x.left, _ = t.remove(x.left, pred.key)
+		x.key = pred.key
+	}
+	return x.adjust(), removed
+}
+
+// All returns an iterator over the keys in ascending order
+// the tree must not be modified during the iteration
+func (t *AvlTree[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		t.root.ascend(yield)
+	}
+}
+
+// Descend returns an iterator over the keys in descending order
+func (t *AvlTree[K]) Descend() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		t.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the keys in [lb, ub] in ascending order, nothing is yielded if lb is greater than ub
+func (t *AvlTree[K]) Range(lb, ub K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if t.cmp(lb, ub) > 0 {
+			return
+		}
+		t.ascendRange(t.root, lb, ub, yield)
+	}
+}
+
+// ascend calls yield for every key of the subtree in order, it returns false once yield does
+func (x *node[K]) ascend(yield func(K) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.left.ascend(yield) && yield(x.key) && x.right.ascend(yield)
+}
+
+func (x *node[K]) descend(yield func(K) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.right.descend(yield) && yield(x.key) && x.left.descend(yield)
+}
+
+// ascendRange only visits the subtrees which may hold keys in [lb, ub]
+func (t *AvlTree[K]) ascendRange(x *node[K], lb, ub K, yield func(K) bool) bool {
+	if x == nil {
+		return true
+	}
+	lower := t.cmp(lb, x.key)
+	upper := t.cmp(ub, x.key)
+	if lower < 0 && !t.ascendRange(x.left, lb, ub, yield) {
+		return false
+	}
+	if lower <= 0 && upper >= 0 && !yield(x.key) {
+		return false
+	}
+	if upper > 0 {
+		return t.ascendRange(x.right, lb, ub, yield)
+	}
+	return true
+}
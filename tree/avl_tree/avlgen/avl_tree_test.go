@@ -0,0 +1,151 @@
+package avlgen
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/avl_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkBalance verifies the heights and the balance of every node, and returns the height of x
+func checkBalance[K any](t *testing.T, x *node[K]) int {
+	if x == nil {
+		return 0
+	}
+	l, r := checkBalance(t, x.left), checkBalance(t, x.right)
+	assert.LessOrEqual(t, max(l-r, r-l), 1)
+	assert.Equal(t, max(l, r)+1, x.height)
+	return x.height
+}
+
+func TestAvlTree(t *testing.T) {
+	tree := NewOrdered[int]()
+	_, ok := tree.Min()
+	assert.False(t, ok)
+	_, ok = tree.Max()
+	assert.False(t, ok)
+	assert.False(t, tree.Remove(1))
+
+	assert.True(t, tree.Add(1))
+	assert.Equal(t, 1, tree.root.height)
+	assert.True(t, tree.Add(3))
+	assert.True(t, tree.Add(2))
+	assert.False(t, tree.Add(1))
+	assert.Equal(t, 3, tree.Size())
+	assert.Equal(t, 2, tree.root.height)
+	assert.Equal(t, []int{1, 2, 3}, tree.ToSlice())
+
+	min, _ := tree.Min()
+	max, _ := tree.Max()
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 3, max)
+	assert.True(t, tree.Contains(2))
+	assert.True(t, tree.Remove(2))
+	assert.False(t, tree.Contains(2))
+	assert.Equal(t, 2, tree.Size())
+
+	tree.Init()
+	assert.Zero(t, tree.Size())
+	assert.Empty(t, tree.ToSlice())
+}
+
+func TestAvlTree_Comparator(t *testing.T) {
+	// a comparator may order on anything, here case-insensitively and longest first
+	tree := New(func(a, b string) int {
+		if c := cmp.Compare(len(b), len(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	for _, s := range []string{"b", "abc", "A", "ab", "a"} {
+		tree.Add(s)
+	}
+	assert.Equal(t, []string{"abc", "ab", "A", "b"}, tree.ToSlice())
+}
+
+type intKey int
+
+func (a intKey) CompareTo(b avl_tree.Key) int {
+	return cmp.Compare(a, b.(intKey))
+}
+
+func TestAvlTree_LegacyKey(t *testing.T) {
+	tree := New(avl_tree.Compare)
+	tree.Add(intKey(2))
+	tree.Add(intKey(1))
+	assert.Equal(t, []avl_tree.Key{intKey(1), intKey(2)}, tree.ToSlice())
+}
+
+func TestAvlTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewOrdered[int]()
+	ref := map[int]bool{}
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			assert.Equal(t, ref[k], tree.Remove(k))
+			delete(ref, k)
+		} else {
+			assert.Equal(t, !ref[k], tree.Add(k))
+			ref[k] = true
+		}
+	}
+	checkBalance(t, tree.root)
+
+	var want []int
+	for k := range ref {
+		want = append(want, k)
+	}
+	slices.Sort(want)
+	assert.Equal(t, want, tree.ToSlice())
+	assert.Equal(t, len(want), tree.Size())
+
+	var desc []int
+	for k := range tree.Descend() {
+		desc = append(desc, k)
+	}
+	slices.Reverse(desc)
+	assert.Equal(t, want, desc)
+
+	var between []int
+	for k := range tree.Range(200, 300) {
+		between = append(between, k)
+	}
+	lo, _ := slices.BinarySearch(want, 200)
+	hi, _ := slices.BinarySearch(want, 301)
+	assert.Equal(t, want[lo:hi], between)
+	for range tree.Range(300, 200) {
+		t.Fatal("an empty range yields nothing")
+	}
+}
+
+const benchSize = 1 << 12
+
+func BenchmarkAvlTree_Contains(b *testing.B) {
+	tree := NewOrdered[int]()
+	for i := 0; i < benchSize; i++ {
+		tree.Add(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Contains(i % benchSize)
+	}
+}
+
+// the legacy tree boxes every key into an interface, which allocates for values above 255
+func BenchmarkLegacyAvlTree_Contains(b *testing.B) {
+	tree := avl_tree.New()
+	for i := 0; i < benchSize; i++ {
+		tree.Add(intKey(i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Contains(intKey(i % benchSize))
+	}
+}
@@ -0,0 +1,69 @@
+package avl_tree
+
+import "iter"
+
+// All returns an iterator over the keys in ascending order
+// the tree must not be modified during the iteration
+func (t *AvlTree) All() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		t.root.ascend(func(x *Node) bool {
+			return yield(x.key)
+		})
+	}
+}
+
+// Descend returns an iterator over the keys in descending order
+func (t *AvlTree) Descend() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		t.root.descend(func(x *Node) bool {
+			return yield(x.key)
+		})
+	}
+}
+
+// Range returns an iterator over the keys in [lb, ub] in ascending order
+// nothing is yielded if lb or ub is nil, or lb is greater than ub
+func (t *AvlTree) Range(lb, ub Key) iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+			return
+		}
+		t.root.ascendRange(orderOf(lb), orderOf(ub), func(x *Node) bool {
+			return yield(x.key)
+		})
+	}
+}
+
+// ascend calls yield for every node of the subtree in order, it returns false once yield does
+func (t *Node) ascend(yield func(*Node) bool) bool {
+	if t == nil {
+		return true
+	}
+	return t.left.ascend(yield) && yield(t) && t.right.ascend(yield)
+}
+
+func (t *Node) descend(yield func(*Node) bool) bool {
+	if t == nil {
+		return true
+	}
+	return t.right.descend(yield) && yield(t) && t.left.descend(yield)
+}
+
+// ascendRange only visits the subtrees which may hold keys in [lb, ub], the bounds are given as orders like those of search
+func (t *Node) ascendRange(lb, ub func(*Node) int, yield func(*Node) bool) bool {
+	if t == nil {
+		return true
+	}
+	lower := lb(t)
+	upper := ub(t)
+	if lower < 0 && !t.left.ascendRange(lb, ub, yield) {
+		return false
+	}
+	if lower <= 0 && upper >= 0 && !yield(t) {
+		return false
+	}
+	if upper > 0 {
+		return t.right.ascendRange(lb, ub, yield)
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+package avl_tree
+
+// Set operations built on join, following Blelloch, Ferizovic and Sun, "Just Join for Parallel Ordered Sets"
+// joining two trees around a key costs O(|h1-h2|), so merging a tree of m keys with a tree of n keys, m <= n,
+// costs O(m log(n/m+1)) instead of the O(m log n) of adding the keys one by one
+// the operations reuse the nodes of their operands, so an operand is left empty unless it holds the result
+
+// Split splits the tree into a tree of the keys less than k and a tree of the keys greater than k,
+// found reports whether k was in the tree, the tree is left empty
+// a nil k splits nothing: two empty trees are returned and the tree keeps its keys
+func (t *AvlTree) Split(k Key) (left, right *AvlTree, found bool) {
+	if k == nil {
+		return New(), New(), false
+	}
+	t.fixRoot()
+	l, found, r := t.root.split(k)
+	t.Init()
+	return &AvlTree{root: l, size: l.getSize()}, &AvlTree{root: r, size: r.getSize()}, found
+}
+
+// Join returns a tree of the keys of left, k and the keys of right, both left and right are left empty
+// every key of left must be less than k and every key of right must be greater than k, otherwise nil is returned
+// a nil tree is treated as an empty one
+func Join(left *AvlTree, k Key, right *AvlTree) *AvlTree {
+	if left == nil {
+		left = New()
+	}
+	if right == nil {
+		right = New()
+	}
+	if k == nil {
+		return nil
+	}
+	if max := left.Max(); max != nil && max.CompareTo(k) >= 0 {
+		return nil
+	}
+	if min := right.Min(); min != nil && min.CompareTo(k) <= 0 {
+		return nil
+	}
+	left.fixRoot()
+	right.fixRoot()
+	root := join(left.root, newNode(k), right.root)
+	left.Init()
+	right.Init()
+	return &AvlTree{root: root, size: root.size}
+}
+
+// Union adds every key of other to the tree, other is left empty
+func (t *AvlTree) Union(other *AvlTree) {
+	if other == nil || other == t {
+		return
+	}
+	t.fixRoot()
+	other.fixRoot()
+	t.root = union(t.root, other.root)
+	t.size = t.root.getSize()
+	other.Init()
+}
+
+// Intersection removes the keys which are not in other from the tree, other is left empty
+func (t *AvlTree) Intersection(other *AvlTree) {
+	if other == t {
+		return
+	}
+	if other == nil {
+		t.Init()
+		return
+	}
+	t.fixRoot()
+	other.fixRoot()
+	t.root = intersect(t.root, other.root)
+	t.size = t.root.getSize()
+	other.Init()
+}
+
+// Difference removes the keys which are in other from the tree, other is left empty
+func (t *AvlTree) Difference(other *AvlTree) {
+	if other == t {
+		t.Init()
+		return
+	}
+	if other == nil {
+		return
+	}
+	t.fixRoot()
+	other.fixRoot()
+	t.root = difference(t.root, other.root)
+	t.size = t.root.getSize()
+	other.Init()
+}
+
+// IsSubset returns whether every key of the tree is in other, neither tree is modified
+// the keys are looked up in ascending order from the last one found, so the cost is also O(m log(n/m+1))
+func (t *AvlTree) IsSubset(other *AvlTree) bool {
+	if t.size == 0 {
+		return true
+	}
+	if other == nil || t.size > other.size {
+		return false
+	}
+	f := finger{path: []fingerEntry{{node: other.root}}}
+	for k := range t.All() {
+		if !f.seek(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// fixRoot corrects the height of a root added to an empty tree, which starts at 0
+// joins compare heights, so they must be exact
+func (t *AvlTree) fixRoot() {
+	t.root.calcHeight()
+}
+
+// link makes l and r the children of t
+func (t *Node) link(l, r *Node) *Node {
+	t.left = l
+	t.right = r
+	t.calcHeight()
+	t.calcSize()
+	return t
+}
+
+// join links l and r through the node m, every key of l must be less than m.key and every key of r greater than it
+func join(l, m, r *Node) *Node {
+	if l.getHeight() > r.getHeight()+1 {
+		return joinRight(l, m, r)
+	}
+	if r.getHeight() > l.getHeight()+1 {
+		return joinLeft(l, m, r)
+	}
+	return m.link(l, r)
+}
+
+// joinRight descends the right spine of l, which is higher than r, to where r fits
+func joinRight(l, m, r *Node) *Node {
+	c := l.right
+	if c.getHeight() <= r.getHeight()+1 {
+		t := m.link(c, r)
+		if t.height <= l.left.getHeight()+1 {
+			return l.link(l.left, t)
+		}
+		return rotateLeft(l.link(l.left, rotateRight(t)))
+	}
+	t := joinRight(c, m, r)
+	l.link(l.left, t)
+	if t.height <= l.left.getHeight()+1 {
+		return l
+	}
+	return rotateLeft(l)
+}
+
+// joinLeft is the mirror of joinRight
+func joinLeft(l, m, r *Node) *Node {
+	c := r.left
+	if c.getHeight() <= l.getHeight()+1 {
+		t := m.link(l, c)
+		if t.height <= r.right.getHeight()+1 {
+			return r.link(t, r.right)
+		}
+		return rotateRight(r.link(rotateLeft(t), r.right))
+	}
+	t := joinLeft(l, m, c)
+	r.link(t, r.right)
+	if t.height <= r.right.getHeight()+1 {
+		return r
+	}
+	return rotateRight(r)
+}
+
+// join2 links l and r, every key of l must be less than every key of r
+func join2(l, r *Node) *Node {
+	if l == nil {
+		return r
+	}
+	rest, last := l.splitLast()
+	return join(rest, last, r)
+}
+
+// splitLast detaches the node of the maximum key from the subtree
+func (t *Node) splitLast() (rest, last *Node) {
+	if t.right == nil {
+		return t.left, t
+	}
+	rest, last = t.right.splitLast()
+	return join(t.left, t, rest), last
+}
+
+// split divides the subtree into the keys less than k and the keys greater than k, the node of k is dropped
+func (t *Node) split(k Key) (l *Node, found bool, r *Node) {
+	if t == nil {
+		return nil, false, nil
+	}
+	res := k.CompareTo(t.key)
+	if res < 0 {
+		l, found, r = t.left.split(k)
+		return l, found, join(r, t, t.right)
+	}
+	if res > 0 {
+		l, found, r = t.right.split(k)
+		return join(t.left, t, l), found, r
+	}
+	return t.left, true, t.right
+}
+
+func union(a, b *Node) *Node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	bl, br := b.left, b.right
+	l, _, r := a.split(b.key)
+	return join(union(l, bl), b, union(r, br))
+}
+
+func intersect(a, b *Node) *Node {
+	if a == nil || b == nil {
+		return nil
+	}
+	bl, br := b.left, b.right
+	l, found, r := a.split(b.key)
+	l, r = intersect(l, bl), intersect(r, br)
+	if found {
+		return join(l, b, r)
+	}
+	return join2(l, r)
+}
+
+func difference(a, b *Node) *Node {
+	if a == nil || b == nil {
+		return a
+	}
+	bl, br := b.left, b.right
+	l, _, r := a.split(b.key)
+	return join2(difference(l, bl), difference(r, br))
+}
+
+// finger looks up keys in ascending order, starting from the node found last
+type finger struct {
+	path []fingerEntry
+}
+
+// fingerEntry is a node on the path from the root, every key of its subtree is less than ub unless ub is nil
+type fingerEntry struct {
+	node *Node
+	ub   Key
+}
+
+// seek reports whether k is in the tree, k must be greater than the keys sought before
+// the search resumes from the deepest node on the path whose subtree may hold k, since k is greater than the keys
+// sought before, that is the deepest one whose upper bound is greater than k, and a nil bound always is
+func (f *finger) seek(k Key) bool {
+	for len(f.path) > 1 {
+		if ub := f.path[len(f.path)-1].ub; ub == nil || k.CompareTo(ub) < 0 {
+			break
+		}
+		f.path = f.path[:len(f.path)-1]
+	}
+	top := f.path[len(f.path)-1]
+	x, ub := top.node, top.ub
+	for x != nil {
+		res := k.CompareTo(x.key)
+		if res == 0 {
+			return true
+		}
+		if res < 0 {
+			ub = x.key
+			x = x.left
+		} else {
+			x = x.right
+		}
+		if x != nil {
+			f.path = append(f.path, fingerEntry{node: x, ub: ub})
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package avl_tree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func treeOf(xs ...int) *AvlTree {
+	t := New()
+	for _, x := range xs {
+		t.Add(intKey(x))
+	}
+	return t
+}
+
+func keysOf(xs ...int) []Key {
+	res := make([]Key, len(xs))
+	for i, x := range xs {
+		res[i] = intKey(x)
+	}
+	return res
+}
+
+// randomSet returns n distinct keys drawn from [0, bound) in ascending order
+func randomSet(r *rand.Rand, n, bound int) []int {
+	seen := map[int]bool{}
+	for len(seen) < n {
+		seen[r.Intn(bound)] = true
+	}
+	res := make([]int, 0, n)
+	for x := range seen {
+		res = append(res, x)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func TestAvlTree_Split(t *testing.T) {
+	tree := treeOf(5, 3, 8, 1, 4, 7, 9)
+	l, r, found := tree.Split(intKey(5))
+	assert.True(t, found)
+	assert.Equal(t, keysOf(1, 3, 4), l.ToSlice())
+	assert.Equal(t, keysOf(7, 8, 9), r.ToSlice())
+	assert.Equal(t, 3, l.Size())
+	assert.Equal(t, 0, tree.Size())
+	checkAvl(t, l.root)
+	checkAvl(t, r.root)
+
+	l, r, found = l.Split(intKey(2))
+	assert.False(t, found)
+	assert.Equal(t, keysOf(1), l.ToSlice())
+	assert.Equal(t, keysOf(3, 4), r.ToSlice())
+
+	l, r, found = treeOf(1).Split(intKey(0))
+	assert.False(t, found)
+	assert.Equal(t, 0, l.Size())
+	assert.Equal(t, keysOf(1), r.ToSlice())
+	checkAvl(t, r.root)
+
+	tree = treeOf(1, 2)
+	l, r, found = tree.Split(nil)
+	assert.False(t, found)
+	assert.Equal(t, 0, l.Size())
+	assert.Equal(t, 0, r.Size())
+	assert.Equal(t, keysOf(1, 2), tree.ToSlice())
+}
+
+func TestJoin(t *testing.T) {
+	big := New()
+	for i := 0; i < 1000; i++ {
+		big.Add(intKey(i))
+	}
+	res := Join(big, intKey(1000), treeOf(1001))
+	assert.Equal(t, 1002, res.Size())
+	assert.Equal(t, 0, big.Size())
+	checkAvl(t, res.root)
+	assert.Equal(t, intKey(500), res.Select(500))
+
+	res = Join(treeOf(-2), intKey(-1), res)
+	assert.Equal(t, 1004, res.Size())
+	assert.Equal(t, intKey(-2), res.Min())
+	checkAvl(t, res.root)
+
+	res = Join(nil, intKey(0), nil)
+	assert.Equal(t, keysOf(0), res.ToSlice())
+	assert.Nil(t, Join(treeOf(1, 2), intKey(2), treeOf(3)))
+	assert.Nil(t, Join(treeOf(1), intKey(2), treeOf(2)))
+	assert.Nil(t, Join(nil, nil, nil))
+}
+
+func TestAvlTree_SetOperations(t *testing.T) {
+	a, b := treeOf(1, 2, 3, 4, 5), treeOf(4, 5, 6)
+	a.Union(b)
+	assert.Equal(t, keysOf(1, 2, 3, 4, 5, 6), a.ToSlice())
+	assert.Equal(t, 6, a.Size())
+	assert.Equal(t, 0, b.Size())
+
+	a, b = treeOf(1, 2, 3, 4, 5), treeOf(4, 5, 6)
+	a.Intersection(b)
+	assert.Equal(t, keysOf(4, 5), a.ToSlice())
+
+	a, b = treeOf(1, 2, 3, 4, 5), treeOf(4, 5, 6)
+	a.Difference(b)
+	assert.Equal(t, keysOf(1, 2, 3), a.ToSlice())
+
+	a.Union(a)
+	assert.Equal(t, 3, a.Size())
+	a.Intersection(a)
+	assert.Equal(t, 3, a.Size())
+	a.Union(nil)
+	a.Difference(nil)
+	assert.Equal(t, 3, a.Size())
+	a.Intersection(nil)
+	assert.Equal(t, 0, a.Size())
+
+	a = treeOf(1, 2, 3)
+	a.Difference(a)
+	assert.Equal(t, 0, a.Size())
+}
+
+func TestAvlTree_IsSubset(t *testing.T) {
+	assert.True(t, treeOf(2, 4).IsSubset(treeOf(1, 2, 3, 4)))
+	assert.False(t, treeOf(2, 5).IsSubset(treeOf(1, 2, 3, 4)))
+	assert.False(t, treeOf(0, 2).IsSubset(treeOf(1, 2, 3, 4)))
+	assert.False(t, treeOf(1, 2, 3).IsSubset(treeOf(1, 2)))
+	assert.True(t, New().IsSubset(nil))
+	assert.False(t, treeOf(1).IsSubset(nil))
+
+	tree := treeOf(1, 2, 3)
+	assert.True(t, tree.IsSubset(tree))
+	assert.Equal(t, 3, tree.Size())
+}
+
+// the set operations agree with merging the sorted slices, for operands of similar and of very different sizes
+// countedKey counts the comparisons made by the finger, which compares the sought key to the keys of the tree
+type countedKey struct {
+	intKey
+	n *int
+}
+
+func (k countedKey) CompareTo(o Key) int {
+	*k.n++
+	return k.intKey.CompareTo(o)
+}
+
+// seeking the keys of the right spine in order resumes from the node found last instead of the root
+func TestFinger_RightSpine(t *testing.T) {
+	tree := New()
+	for i := 0; i < 1<<12; i++ {
+		tree.Add(intKey(i))
+	}
+	var spine []intKey
+	for x := tree.root; x != nil; x = x.right {
+		spine = append(spine, x.key.(intKey))
+	}
+
+	n := 0
+	f := finger{path: []fingerEntry{{node: tree.root}}}
+	for _, k := range spine {
+		assert.True(t, f.seek(countedKey{k, &n}))
+	}
+	// one comparison to the bound of the deepest entry, one to find the node below it
+	assert.LessOrEqual(t, n, 2*len(spine))
+}
+
+func TestAvlTree_SetOperationsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range [][2]int{{0, 100}, {1, 100}, {10, 1000}, {500, 500}, {1000, 30}} {
+		xs, ys := randomSet(r, n[0], 2000), randomSet(r, n[1], 2000)
+		var union, inter, diff []int
+		for _, x := range xs {
+			if _, ok := slices.BinarySearch(ys, x); ok {
+				inter = append(inter, x)
+			} else {
+				diff = append(diff, x)
+			}
+		}
+		union = append(append(union, xs...), ys...)
+		slices.Sort(union)
+		union = slices.Compact(union)
+
+		a, b := treeOf(xs...), treeOf(ys...)
+		assert.Equal(t, len(inter) == len(xs), a.IsSubset(b))
+		a.Union(b)
+		assert.Equal(t, keysOf(union...), a.ToSlice())
+		assert.Equal(t, len(union), a.Size())
+		checkAvl(t, a.root)
+
+		a, b = treeOf(xs...), treeOf(ys...)
+		a.Intersection(b)
+		assert.Equal(t, keysOf(inter...), a.ToSlice())
+		assert.Equal(t, len(inter), a.Size())
+		checkAvl(t, a.root)
+
+		a, b = treeOf(xs...), treeOf(ys...)
+		a.Difference(b)
+		assert.Equal(t, keysOf(diff...), a.ToSlice())
+		assert.Equal(t, len(diff), a.Size())
+		checkAvl(t, a.root)
+
+		a = treeOf(union...)
+		assert.True(t, treeOf(xs...).IsSubset(a))
+		assert.True(t, treeOf(ys...).IsSubset(a))
+	}
+}
@@ -0,0 +1,63 @@
+package rb_tree
+
+import "iter"
+
+// All returns an iterator over the key-value pairs in ascending order of keys
+// the tree must not be modified during the iteration
+func (t *RBTree) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.root.ascend(yield)
+	}
+}
+
+// Backward returns an iterator over the key-value pairs in descending order of keys
+func (t *RBTree) Backward() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the key-value pairs whose keys are in [lb, ub], in ascending order
+// like KeysBetween, nothing is yielded if lb or ub is nil
+func (t *RBTree) Range(lb, ub Key) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if lb == nil || ub == nil || lb.CompareTo(ub) > 0 {
+			return
+		}
+		t.root.ascendRange(lb, ub, yield)
+	}
+}
+
+// ascend calls yield for every pair of the subtree in order, it returns false once yield does
+func (x *node) ascend(yield func(Key, Value) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.left.ascend(yield) && yield(x.key, x.value) && x.right.ascend(yield)
+}
+
+func (x *node) descend(yield func(Key, Value) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.right.descend(yield) && yield(x.key, x.value) && x.left.descend(yield)
+}
+
+// ascendRange only visits the subtrees which may hold keys in [lb, ub]
+func (x *node) ascendRange(lb, ub Key, yield func(Key, Value) bool) bool {
+	if x == nil {
+		return true
+	}
+	lower := lb.CompareTo(x.key)
+	upper := ub.CompareTo(x.key)
+	if lower < 0 && !x.left.ascendRange(lb, ub, yield) {
+		return false
+	}
+	if lower <= 0 && upper >= 0 && !yield(x.key, x.value) {
+		return false
+	}
+	if upper > 0 {
+		return x.right.ascendRange(lb, ub, yield)
+	}
+	return true
+}
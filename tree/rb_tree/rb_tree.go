@@ -2,10 +2,16 @@
 // complexity of inserting, searching and deleting are all O(logN)
 package rb_tree
 
+// Key orders the keys of a SortedMap, rbgen.RBTree keeps keys and values of concrete types instead of Key and Value
 type Key interface {
 	CompareTo(Key) int
 }
 
+// Compare is CompareTo as a plain function, the form of comparator that rbgen.New expects
+func Compare(a, b Key) int {
+	return a.CompareTo(b)
+}
+
 type Value interface{}
 
 type Entry struct {
@@ -459,3 +459,59 @@ func checkViolation(x *node) bool {
 
 	return false
 }
+
+func TestRBTree_All(t *testing.T) {
+	var rbt RBTree
+	putEachChar(&rbt, "DBFACEG")
+
+	var keys []Key
+	for k, v := range rbt.All() {
+		assert.Equal(t, string(k.(str)), v)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, rbt.Keys(), keys)
+
+	keys = nil
+	for k := range rbt.Backward() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []Key{str("G"), str("F"), str("E"), str("D"), str("C"), str("B"), str("A")}, keys)
+
+	keys = nil
+	for k := range rbt.Backward() {
+		if k == str("E") {
+			break
+		}
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []Key{str("G"), str("F")}, keys)
+}
+
+func TestRBTree_Range(t *testing.T) {
+	var rbt RBTree
+	s := "9876543210DCBA"
+	putEachChar(&rbt, s)
+
+	var keys []Key
+	for k, v := range rbt.Range(str("5"), str("B")) {
+		assert.Equal(t, string(k.(str)), v)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, rbt.KeysBetween(str("5"), str("B")), keys)
+
+	keys = nil
+	for k := range rbt.Range(str("5"), str("B")) {
+		if len(keys) == 2 {
+			break
+		}
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []Key{str("5"), str("6")}, keys)
+
+	for range rbt.Range(str("B"), str("5")) {
+		t.Fatal("empty range yielded a key")
+	}
+	for range rbt.Range(nil, str("5")) {
+		t.Fatal("nil bound yielded a key")
+	}
+}
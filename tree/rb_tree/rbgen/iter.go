@@ -0,0 +1,62 @@
+package rbgen
+
+import "iter"
+
+// All returns an iterator over the key-value pairs in ascending order of keys
+// the tree must not be modified during the iteration
+func (t *RBTree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.ascend(yield)
+	}
+}
+
+// Backward returns an iterator over the key-value pairs in descending order of keys
+func (t *RBTree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the key-value pairs whose keys are in [lb, ub], in ascending order
+func (t *RBTree[K, V]) Range(lb, ub K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.cmp(lb, ub) > 0 {
+			return
+		}
+		t.ascendRange(t.root, lb, ub, yield)
+	}
+}
+
+// ascend calls yield for every pair of the subtree in order, it returns false once yield does
+func (x *node[K, V]) ascend(yield func(K, V) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.left.ascend(yield) && yield(x.key, x.value) && x.right.ascend(yield)
+}
+
+func (x *node[K, V]) descend(yield func(K, V) bool) bool {
+	if x == nil {
+		return true
+	}
+	return x.right.descend(yield) && yield(x.key, x.value) && x.left.descend(yield)
+}
+
+// ascendRange only visits the subtrees which may hold keys in [lb, ub]
+func (t *RBTree[K, V]) ascendRange(x *node[K, V], lb, ub K, yield func(K, V) bool) bool {
+	if x == nil {
+		return true
+	}
+	lower := t.cmp(lb, x.key)
+	upper := t.cmp(ub, x.key)
+	if lower < 0 && !t.ascendRange(x.left, lb, ub, yield) {
+		return false
+	}
+	if lower <= 0 && upper >= 0 && !yield(x.key, x.value) {
+		return false
+	}
+	if upper > 0 {
+		return t.ascendRange(x.right, lb, ub, yield)
+	}
+	return true
+}
@@ -0,0 +1,414 @@
+// A sorted map of typed keys and values, implemented with left-leaning red-black tree like rb_tree.RBTree
+// where rb_tree asks every key to implement CompareTo, RBTree[K, V] is handed a single comparison function when it is made,
+// e.g. New[string, int](strings.Compare), and rb_tree.Compare lets the keys of rb_tree be reused as they are
+// complexity of inserting, searching and deleting are all O(logN)
+package rbgen
+
+import "cmp"
+
+// SortedMap is the method set of rb_tree.SortedMap for typed keys and values
+// a method which returned a nil Key or Value for a missing one also returns ok == false instead
+type SortedMap[K, V any] interface {
+	Init()
+	Get(K) (V, bool)
+	Put(K, V)
+	Delete(K)
+	Contains(K) bool
+	IsEmpty() bool
+	Size() int
+	Min() (K, bool)
+	Max() (K, bool)
+	Floor(K) (K, bool)
+	Ceiling(K) (K, bool)
+	Select(int) (K, bool)
+	Rank(K) int
+	DeleteMin()
+	DeleteMax()
+	SizeBetween(K, K) int
+	Keys() []K
+	KeysBetween(K, K) []K
+}
+
+const (
+	red   = true
+	black = false
+)
+
+type node[K, V any] struct {
+	color bool
+	size  int
+	left  *node[K, V]
+	right *node[K, V]
+	key   K
+	value V
+}
+
+// RBTree should implements SortedMap
+// the zero value is not usable, an RBTree must be created with New or NewOrdered
+type RBTree[K, V any] struct {
+	root *node[K, V]
+	cmp  func(a, b K) int
+}
+
+// New returns an empty map ordered by cmp
+func New[K, V any](cmp func(a, b K) int) *RBTree[K, V] {
+	return &RBTree[K, V]{cmp: cmp}
+}
+
+// NewOrdered returns an empty map in the natural order of K
+func NewOrdered[K cmp.Ordered, V any]() *RBTree[K, V] {
+	return New[K, V](cmp.Compare[K])
+}
+
+func (x *node[K, V]) isRed() bool {
+	return x != nil && x.color == red
+}
+
+func (x *node[K, V]) getSize() int {
+	if x == nil {
+		return 0
+	}
+	return x.size
+}
+
+func (x *node[K, V]) calcSize() {
+	x.size = x.left.getSize() + x.right.getSize() + 1
+}
+
+func (x *node[K, V]) flipColors() {
+	x.color = !x.color
+	x.left.color = !x.left.color
+	x.right.color = !x.right.color
+}
+
+func (x *node[K, V]) rotateLeft() *node[K, V] {
+	res := x.right
+	x.right = res.left
+	res.left = x
+	res.color, x.color = x.color, red
+	res.size = x.size
+	x.calcSize()
+	return res
+}
+
+func (x *node[K, V]) rotateRight() *node[K, V] {
+	res := x.left
+	x.left = res.right
+	res.right = x
+	res.color, x.color = x.color, red
+	res.size = x.size
+	x.calcSize()
+	return res
+}
+
+// balance restores the left-leaning invariants on the way up
+func (x *node[K, V]) balance() *node[K, V] {
+	if x.right.isRed() && !x.left.isRed() {
+		x = x.rotateLeft()
+	}
+	if x.left.isRed() && x.left.left.isRed() {
+		x = x.rotateRight()
+	}
+	if x.left.isRed() && x.right.isRed() {
+		x.flipColors()
+	}
+	x.calcSize()
+	return x
+}
+
+// x is red and both x.left and x.left.left are black
+func (x *node[K, V]) moveRedLeft() *node[K, V] {
+	x.flipColors()
+	if x.right.left.isRed() {
+		x.right = x.right.rotateRight()
+		x = x.rotateLeft()
+		x.flipColors()
+	}
+	return x
+}
+
+// x is red and both x.right and x.right.left are black
+func (x *node[K, V]) moveRedRight() *node[K, V] {
+	x.flipColors()
+	if x.left.left.isRed() {
+		x = x.rotateRight()
+		x.flipColors()
+	}
+	return x
+}
+
+func (x *node[K, V]) findMin() *node[K, V] {
+	for x.left != nil {
+		x = x.left
+	}
+	return x
+}
+
+func (x *node[K, V]) findMax() *node[K, V] {
+	for x.right != nil {
+		x = x.right
+	}
+	return x
+}
+
+func (x *node[K, V]) deleteMin() *node[K, V] {
+	if x.left == nil {
+		return nil
+	}
+	if !x.left.isRed() && !x.left.left.isRed() {
+		x = x.moveRedLeft()
+	}
+	x.left = x.left.deleteMin()
+	return x.balance()
+}
+
+func (x *node[K, V]) deleteMax() *node[K, V] {
+	if x.left.isRed() {
+		x = x.rotateRight()
+	}
+	if x.right == nil {
+		return nil
+	}
+	if !x.right.isRed() && !x.right.left.isRed() {
+		x = x.moveRedRight()
+	}
+	x.right = x.right.deleteMax()
+	return x.balance()
+}
+
+// getNth returns the node of rank n in the subtree, 0 <= n < x.size must hold
+func (x *node[K, V]) getNth(n int) *node[K, V] {
+	for {
+		rank := x.left.getSize()
+		if n < rank {
+			x = x.left
+		} else if n > rank {
+			n -= rank + 1
+			x = x.right
+		} else {
+			return x
+		}
+	}
+}
+
+func (t *RBTree[K, V]) find(k K) *node[K, V] {
+	x := t.root
+	for x != nil {
+		c := t.cmp(k, x.key)
+		if c < 0 {
+			x = x.left
+		} else if c > 0 {
+			x = x.right
+		} else {
+			return x
+		}
+	}
+	return nil
+}
+
+func (t *RBTree[K, V]) insert(x *node[K, V], k K, v V) *node[K, V] {
+	if x == nil {
+		return &node[K, V]{color: red, size: 1, key: k, value: v}
+	}
+	c := t.cmp(k, x.key)
+	if c < 0 {
+		x.left = t.insert(x.left, k, v)
+	} else if c > 0 {
+		x.right = t.insert(x.right, k, v)
+	} else {
+		x.value = v
+	}
+	return x.balance()
+}
+
+// delete removes k, which must be in the subtree
+func (t *RBTree[K, V]) delete(x *node[K, V], k K) *node[K, V] {
+	if t.cmp(k, x.key) < 0 {
+		if !x.left.isRed() && !x.left.left.isRed() {
+			x = x.moveRedLeft()
+		}
+		x.left = t.delete(x.left, k)
+		return x.balance()
+	}
+	if x.left.isRed() {
+		x = x.rotateRight()
+	}
+	if t.cmp(k, x.key) == 0 && x.right == nil {
+		return nil
+	}
+	if !x.right.isRed() && !x.right.left.isRed() {
+		x = x.moveRedRight()
+	}
+	if t.cmp(k, x.key) == 0 {
+		rmin := x.right.findMin()
+		x.key = rmin.key
+		x.value = rmin.value
+		x.right = x.right.deleteMin()
+	} else {
+		x.right = t.delete(x.right, k)
+	}
+	return x.balance()
+}
+
+// countLess returns the number of keys which are less than k, or less than or equal to k when inclusive is set
+func (t *RBTree[K, V]) countLess(k K, inclusive bool) int {
+	n := 0
+	x := t.root
+	for x != nil {
+		c := t.cmp(k, x.key)
+		if c < 0 || c == 0 && !inclusive {
+			x = x.left
+		} else {
+			n += x.left.getSize() + 1
+			x = x.right
+		}
+	}
+	return n
+}
+
+// Init initializes the tree, it deletes all keys from the tree
+func (t *RBTree[K, V]) Init() {
+	t.root = nil
+}
+
+// Get returns the Value associated with k, ok is false when k is not in the tree
+func (t *RBTree[K, V]) Get(k K) (v V, ok bool) {
+	x := t.find(k)
+	if x == nil {
+		return v, false
+	}
+	return x.value, true
+}
+
+// Put stores v in the tree associated with k, replacing the previous value of k
+func (t *RBTree[K, V]) Put(k K, v V) {
+	t.root = t.insert(t.root, k, v)
+	t.root.color = black
+}
+
+// Delete deletes the Value associated with k from the tree if the key exists
+func (t *RBTree[K, V]) Delete(k K) {
+	if t.find(k) == nil {
+		return
+	}
+	if !t.root.left.isRed() && !t.root.right.isRed() {
+		t.root.color = red
+	}
+	t.root = t.delete(t.root, k)
+	if t.root != nil {
+		t.root.color = black
+	}
+}
+
+// Contains returns whether a Value associated with k is stored in the tree
+func (t *RBTree[K, V]) Contains(k K) bool {
+	return t.find(k) != nil
+}
+
+// IsEmpty returns whether the tree is empty
+func (t *RBTree[K, V]) IsEmpty() bool {
+	return t.root == nil
+}
+
+// Size returns the number of keys in the tree
+func (t *RBTree[K, V]) Size() int {
+	return t.root.getSize()
+}
+
+// Min returns the minimum key of the tree, ok is false when the tree is empty
+func (t *RBTree[K, V]) Min() (k K, ok bool) {
+	if t.root == nil {
+		return k, false
+	}
+	return t.root.findMin().key, true
+}
+
+// Max returns the maximum key of the tree, ok is false when the tree is empty
+func (t *RBTree[K, V]) Max() (k K, ok bool) {
+	if t.root == nil {
+		return k, false
+	}
+	return t.root.findMax().key, true
+}
+
+// Floor returns the maximum key which is less than or equals k, ok is false when there is none
+func (t *RBTree[K, V]) Floor(k K) (K, bool) {
+	return t.Select(t.countLess(k, true) - 1)
+}
+
+// Ceiling returns the minimum key which is greater than or equals k, ok is false when there is none
+func (t *RBTree[K, V]) Ceiling(k K) (K, bool) {
+	return t.Select(t.countLess(k, false))
+}
+
+// Select returns the key which ranks r-th (staring from 0, in order of the comparator) in the tree
+// ok is false when r < 0 or r >= t.Size()
+func (t *RBTree[K, V]) Select(r int) (k K, ok bool) {
+	if r < 0 || r >= t.Size() {
+		return k, false
+	}
+	return t.root.getNth(r).key, true
+}
+
+// If k is in the tree, the rank of k is returned (staring from 0, in order of the comparator)
+// otherwise -1 is returned
+func (t *RBTree[K, V]) Rank(k K) int {
+	if t.find(k) == nil {
+		return -1
+	}
+	return t.countLess(k, false)
+}
+
+// DeleteMin deletes the minimum key from the tree
+func (t *RBTree[K, V]) DeleteMin() {
+	if t.root == nil {
+		return
+	}
+	if !t.root.left.isRed() && !t.root.right.isRed() {
+		t.root.color = red
+	}
+	t.root = t.root.deleteMin()
+	if t.root != nil {
+		t.root.color = black
+	}
+}
+
+// DeleteMax deletes the maximum key from the tree
+func (t *RBTree[K, V]) DeleteMax() {
+	if t.root == nil {
+		return
+	}
+	if !t.root.left.isRed() && !t.root.right.isRed() {
+		t.root.color = red
+	}
+	t.root = t.root.deleteMax()
+	if t.root != nil {
+		t.root.color = black
+	}
+}
+
+// SizeBetween returns the number of keys in interval [lb, ub] (both sides included)
+func (t *RBTree[K, V]) SizeBetween(lb, ub K) int {
+	if t.cmp(lb, ub) > 0 {
+		return 0
+	}
+	return t.countLess(ub, true) - t.countLess(lb, false)
+}
+
+// Keys returns a sorted slice of all keys in the tree
+func (t *RBTree[K, V]) Keys() []K {
+	keys := make([]K, 0, t.Size())
+	for k := range t.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// KeysBetween returns a sorted slice of the keys in [lb, ub]
+func (t *RBTree[K, V]) KeysBetween(lb, ub K) []K {
+	keys := make([]K, 0)
+	for k := range t.Range(lb, ub) {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,222 @@
+package rbgen
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/derekcdz/dsgym/tree/rb_tree"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ SortedMap[int, string] = (*RBTree[int, string])(nil)
+
+// checkTree verifies the sizes and the left-leaning red-black invariants, it returns the black height of x
+func checkTree[K, V any](t *testing.T, x *node[K, V]) int {
+	if x == nil {
+		return 0
+	}
+	assert.False(t, x.right.isRed(), "red links lean left")
+	assert.False(t, x.isRed() && x.left.isRed(), "no two red links in a row")
+	l, r := checkTree(t, x.left), checkTree(t, x.right)
+	assert.Equal(t, l, r, "perfect black balance")
+	assert.Equal(t, x.left.getSize()+x.right.getSize()+1, x.size)
+	if x.isRed() {
+		return l
+	}
+	return l + 1
+}
+
+func TestRBTree(t *testing.T) {
+	m := NewOrdered[string, int]()
+	assert.True(t, m.IsEmpty())
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+	_, ok = m.Min()
+	assert.False(t, ok)
+	_, ok = m.Floor("a")
+	assert.False(t, ok)
+	m.Delete("a")
+	m.DeleteMin()
+	m.DeleteMax()
+
+	for i, k := range []string{"E", "A", "S", "Y", "Q", "U", "T", "I", "O", "N"} {
+		m.Put(k, i)
+	}
+	m.Put("A", 100)
+	checkTree(t, m.root)
+	assert.Equal(t, 10, m.Size())
+	v, ok := m.Get("A")
+	assert.True(t, ok)
+	assert.Equal(t, 100, v)
+	assert.True(t, m.Contains("Q"))
+	assert.False(t, m.Contains("B"))
+	assert.Equal(t, []string{"A", "E", "I", "N", "O", "Q", "S", "T", "U", "Y"}, m.Keys())
+
+	min, _ := m.Min()
+	max, _ := m.Max()
+	assert.Equal(t, "A", min)
+	assert.Equal(t, "Y", max)
+	k, _ := m.Floor("P")
+	assert.Equal(t, "O", k)
+	k, _ = m.Floor("O")
+	assert.Equal(t, "O", k)
+	k, _ = m.Ceiling("P")
+	assert.Equal(t, "Q", k)
+	_, ok = m.Ceiling("Z")
+	assert.False(t, ok)
+
+	k, _ = m.Select(3)
+	assert.Equal(t, "N", k)
+	_, ok = m.Select(10)
+	assert.False(t, ok)
+	assert.Equal(t, 3, m.Rank("N"))
+	assert.Equal(t, -1, m.Rank("B"))
+
+	assert.Equal(t, 4, m.SizeBetween("B", "P"))
+	assert.Equal(t, 0, m.SizeBetween("P", "B"))
+	assert.Equal(t, []string{"E", "I", "N", "O"}, m.KeysBetween("B", "P"))
+	assert.Equal(t, []string{}, m.KeysBetween("P", "B"))
+
+	m.DeleteMin()
+	m.DeleteMax()
+	m.Delete("O")
+	m.Delete("B")
+	checkTree(t, m.root)
+	assert.Equal(t, []string{"E", "I", "N", "Q", "S", "T", "U"}, m.Keys())
+
+	m.Init()
+	assert.True(t, m.IsEmpty())
+}
+
+func TestRBTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := NewOrdered[int, int]()
+	ref := map[int]int{}
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		switch r.Intn(6) {
+		case 0:
+			m.Delete(k)
+			delete(ref, k)
+		case 1:
+			if min, ok := m.Min(); ok {
+				m.DeleteMin()
+				delete(ref, min)
+			}
+		case 2:
+			if max, ok := m.Max(); ok {
+				m.DeleteMax()
+				delete(ref, max)
+			}
+		default:
+			m.Put(k, i)
+			ref[k] = i
+		}
+	}
+	checkTree(t, m.root)
+
+	var keys []int
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	assert.Equal(t, keys, m.Keys())
+	for i, k := range keys {
+		v, _ := m.Get(k)
+		assert.Equal(t, ref[k], v)
+		assert.Equal(t, i, m.Rank(k))
+		s, _ := m.Select(i)
+		assert.Equal(t, k, s)
+	}
+	for i := 0; i < 100; i++ {
+		k := r.Intn(1100) - 50
+		j, found := slices.BinarySearch(keys, k)
+		floor, ok := m.Floor(k)
+		if found {
+			assert.Equal(t, k, floor)
+		} else if j > 0 {
+			assert.Equal(t, keys[j-1], floor)
+		} else {
+			assert.False(t, ok)
+		}
+		ceiling, ok := m.Ceiling(k)
+		if j < len(keys) {
+			assert.Equal(t, keys[j], ceiling)
+		} else {
+			assert.False(t, ok)
+		}
+	}
+
+	var back []int
+	for k, v := range m.Backward() {
+		assert.Equal(t, ref[k], v)
+		back = append(back, k)
+	}
+	slices.Reverse(back)
+	assert.Equal(t, keys, back)
+}
+
+type intKey int
+
+func (a intKey) CompareTo(b rb_tree.Key) int {
+	return cmp.Compare(a, b.(intKey))
+}
+
+func TestRBTree_LegacyKey(t *testing.T) {
+	m := New[rb_tree.Key, string](rb_tree.Compare)
+	m.Put(intKey(2), "b")
+	m.Put(intKey(1), "a")
+	assert.Equal(t, []rb_tree.Key{intKey(1), intKey(2)}, m.Keys())
+}
+
+const benchSize = 1 << 12
+
+func BenchmarkRBTree_Put(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := NewOrdered[int, int]()
+		for k := 0; k < benchSize; k++ {
+			m.Put(k, k)
+		}
+	}
+}
+
+// the legacy tree boxes both the key and the value of every pair into interfaces
+func BenchmarkLegacyRBTree_Put(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var m rb_tree.RBTree
+		for k := 0; k < benchSize; k++ {
+			m.Put(intKey(k), k)
+		}
+	}
+}
+
+func BenchmarkRBTree_Get(b *testing.B) {
+	m := NewOrdered[int, int]()
+	for k := 0; k < benchSize; k++ {
+		m.Put(k, k)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	sum := 0
+	for i := 0; i < b.N; i++ {
+		v, _ := m.Get(i % benchSize)
+		sum += v
+	}
+}
+
+func BenchmarkLegacyRBTree_Get(b *testing.B) {
+	var m rb_tree.RBTree
+	for k := 0; k < benchSize; k++ {
+		m.Put(intKey(k), k)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	sum := 0
+	for i := 0; i < b.N; i++ {
+		sum += m.Get(intKey(i % benchSize)).(int)
+	}
+}